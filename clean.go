@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"goctl-validate/generator"
+
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// runClean clean子命令：删除插件在输出目录中生成的文件和清单，
+// 输出目录与生成时相同，由 -dir、-output 和配置文件决定
+func runClean(args []string) {
+	fs := flag.NewFlagSet("goctl-validate clean", flag.ExitOnError)
+	dir := fs.String("dir", ".", "the target directory")
+	style := fs.String("style", "", "the file naming format, see goctl --style")
+	bindOptionFlags(fs)
+	fs.Parse(args)
+
+	absDir, err := filepath.Abs(*dir)
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(fs, absDir)
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+
+	gen := generator.NewValidateGenerator(&plugin.Plugin{Dir: absDir, Style: *style}, cfg.options())
+	if err := gen.Clean(); err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goctl-validate/generator"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName 项目配置文件，从API文件所在目录向上查找
+const configFileName = ".goctl-validate.yaml"
+
+// optionSpec 生成选项的定义，同一个选项可以通过命令行参数、环境变量和配置文件设置，
+// 优先级为 命令行参数 > 环境变量 > 配置文件 > 默认值
+type optionSpec struct {
+	Name    string // 命令行参数名，同时也是配置文件中的键
+	Env     string // 环境变量名
+	Bool    bool   // 是否为开关选项
+	Default string
+	Usage   string
+}
+
+// optionSpecs 所有生成选项
+var optionSpecs = []optionSpec{
+	{Name: "translator", Env: "GOCTL_VALIDATE_TRANSLATOR", Bool: true, Default: "false", Usage: "generate translator for validation messages"},
+	{Name: "strict", Env: "GOCTL_VALIDATE_STRICT", Bool: true, Default: "false", Usage: "treat warnings as errors"},
+	{Name: "merge", Env: "GOCTL_VALIDATE_MERGE", Bool: true, Default: "false", Usage: "merge types of multiple entry API files generating into the same directory"},
+	{Name: "check", Env: "GOCTL_VALIDATE_CHECK", Bool: true, Default: "false", Usage: "check that generated files are up to date without writing them"},
+	{Name: "stdout", Env: "GOCTL_VALIDATE_STDOUT", Bool: true, Default: "false", Usage: "print generated code to stdout without writing files"},
+	{Name: "force", Env: "GOCTL_VALIDATE_FORCE", Bool: true, Default: "false", Usage: "overwrite existing files that were not generated by goctl-validate"},
+	{Name: "output", Env: "GOCTL_VALIDATE_OUTPUT", Default: "internal/types", Usage: "output directory relative to -dir (default: internal/types)"},
+	{Name: "package", Env: "GOCTL_VALIDATE_PACKAGE", Usage: "package name of generated code (default: detected from output directory)"},
+	{Name: "static", Env: "GOCTL_VALIDATE_STATIC", Bool: true, Default: "false", Usage: "generate reflection-free checks for supported rules, falling back to the validator"},
+	{Name: "receiver", Env: "GOCTL_VALIDATE_RECEIVER", Default: "r", Usage: "receiver name of the generated methods"},
+	{Name: "method", Env: "GOCTL_VALIDATE_METHOD", Default: "Validate", Usage: "name of the generated validation method"},
+	{Name: "validator-var", Env: "GOCTL_VALIDATE_VALIDATOR_VAR", Default: "validate", Usage: "name of the shared validator variable"},
+	{Name: "translator-var", Env: "GOCTL_VALIDATE_TRANSLATOR_VAR", Default: "translator", Usage: "name of the translator variable"},
+	{Name: "home", Env: "GOCTL_VALIDATE_HOME", Usage: "the goctl home path of the templates, same as goctl --home"},
+	{Name: "remote", Env: "GOCTL_VALIDATE_REMOTE", Usage: "the remote git repo of the templates, takes precedence over home, same as goctl --remote"},
+	{Name: "branch", Env: "GOCTL_VALIDATE_BRANCH", Usage: "the branch of the remote repo, used with remote"},
+}
+
+// bindOptionFlags 为每个生成选项注册命令行参数，命令行参数不设置默认值，以便区分是否显式指定
+func bindOptionFlags(fs *flag.FlagSet) {
+	for _, spec := range optionSpecs {
+		if spec.Bool {
+			fs.Bool(spec.Name, false, spec.Usage)
+		} else {
+			fs.String(spec.Name, "", spec.Usage)
+		}
+	}
+}
+
+// optionValue 选项的生效值以及来源
+type optionValue struct {
+	Value  string
+	Source string // flag、env GOCTL_VALIDATE_XXX、配置文件路径或default
+}
+
+// config 合并命令行参数、环境变量、配置文件和默认值之后的生效配置
+type config struct {
+	File   string // 找到的配置文件，没有时为空
+	values map[string]optionValue
+}
+
+// loadConfig 按照 命令行参数 > 环境变量 > 配置文件 > 默认值 的优先级计算每个选项的生效值，
+// 配置文件从startDir向上查找
+func loadConfig(fs *flag.FlagSet, startDir string) (*config, error) {
+	cfg := &config{values: make(map[string]optionValue)}
+
+	fileValues := map[string]string{}
+	if file := findConfigFile(startDir); file != "" {
+		values, err := readConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cfg.File, fileValues = file, values
+	}
+
+	setFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	for _, spec := range optionSpecs {
+		value := optionValue{Value: spec.Default, Source: "default"}
+		if v, ok := fileValues[spec.Name]; ok {
+			value = optionValue{Value: v, Source: cfg.File}
+		}
+		if v, ok := os.LookupEnv(spec.Env); ok && v != "" {
+			value = optionValue{Value: v, Source: "env " + spec.Env}
+		}
+		if setFlags[spec.Name] {
+			value = optionValue{Value: fs.Lookup(spec.Name).Value.String(), Source: "flag -" + spec.Name}
+		}
+
+		if spec.Bool {
+			b, err := strconv.ParseBool(value.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for option %s from %s", value.Value, spec.Name, value.Source)
+			}
+			value.Value = strconv.FormatBool(b)
+		}
+		cfg.values[spec.Name] = value
+	}
+	return cfg, nil
+}
+
+// findConfigFile 从dir开始向上查找配置文件，找不到时返回空字符串
+func findConfigFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		file := filepath.Join(dir, configFileName)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// readConfigFile 读取配置文件，未知的选项直接报错，避免拼写错误被静默忽略
+func readConfigFile(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", file, err)
+	}
+
+	known := map[string]bool{}
+	for _, spec := range optionSpecs {
+		known[spec.Name] = true
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown option %q in %s", key, file)
+		}
+		values[key] = fmt.Sprint(value)
+	}
+	return values, nil
+}
+
+// bool 开关选项的生效值
+func (c *config) bool(name string) bool {
+	return c.values[name].Value == "true"
+}
+
+// string 字符串选项的生效值
+func (c *config) string(name string) string {
+	return c.values[name].Value
+}
+
+// options 转换为生成器选项
+func (c *config) options() *generator.Options {
+	return &generator.Options{
+		EnableTranslator: c.bool("translator"),
+		Strict:           c.bool("strict"),
+		OutputDir:        c.string("output"),
+		Package:          c.string("package"),
+		Merge:            c.bool("merge"),
+		Check:            c.bool("check"),
+		Stdout:           c.bool("stdout"),
+		Force:            c.bool("force"),
+		Receiver:         c.string("receiver"),
+		Method:           c.string("method"),
+		ValidatorVar:     c.string("validator-var"),
+		TranslatorVar:    c.string("translator-var"),
+		Static:           c.bool("static"),
+	}
+}
+
+// print 输出每个选项的生效值及其来源
+func (c *config) print() {
+	if c.File != "" {
+		fmt.Printf("config file: %s\n\n", c.File)
+	} else {
+		fmt.Printf("config file: none (%s not found)\n\n", configFileName)
+	}
+
+	fmt.Printf("%-15s %-16s %s\n", "OPTION", "VALUE", "SOURCE")
+	for _, spec := range optionSpecs {
+		value := c.values[spec.Name]
+		display := value.Value
+		if display == "" {
+			display = `""`
+		}
+		fmt.Printf("%-15s %-16s %s\n", spec.Name, display, value.Source)
+	}
+}
+
+// runConfig config子命令，目前支持 config print
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Println("Usage: goctl-validate config print [-api example.api] [options]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("goctl-validate config print", flag.ExitOnError)
+	apiFile := fs.String("api", "", "the api file, the config file is searched upward from its directory")
+	bindOptionFlags(fs)
+	fs.Parse(args[1:])
+
+	startDir := "."
+	if *apiFile != "" {
+		startDir = filepath.Dir(*apiFile)
+	}
+	cfg, err := loadConfig(fs, startDir)
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+	cfg.print()
+}
+
+// envList 环境变量列表，用于帮助信息
+func envList() string {
+	var names []string
+	for _, spec := range optionSpecs {
+		names = append(names, spec.Env)
+	}
+	return strings.Join(names, ", ")
+}
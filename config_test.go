@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newOptionFlags 创建注册了生成选项的参数集合并解析args
+func newOptionFlags(t *testing.T, args ...string) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	bindOptionFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return fs
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	for _, spec := range optionSpecs {
+		t.Setenv(spec.Env, "")
+	}
+
+	root := t.TempDir()
+	apiDir := filepath.Join(root, "api", "user")
+	if err := os.MkdirAll(apiDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(root, configFileName)
+	content := "output: from/file\npackage: filepkg\nstrict: true\ntranslator: true\n"
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOCTL_VALIDATE_OUTPUT", "from/env")
+	t.Setenv("GOCTL_VALIDATE_STRICT", "false")
+
+	cfg, err := loadConfig(newOptionFlags(t, "-strict", "-package", "flagpkg"), apiDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.File != configFile {
+		t.Errorf("config file %q, want %q", cfg.File, configFile)
+	}
+
+	want := map[string]optionValue{
+		"strict":     {Value: "true", Source: "flag -strict"},
+		"package":    {Value: "flagpkg", Source: "flag -package"},
+		"output":     {Value: "from/env", Source: "env GOCTL_VALIDATE_OUTPUT"},
+		"translator": {Value: "true", Source: configFile},
+		"merge":      {Value: "false", Source: "default"},
+	}
+	for name, value := range want {
+		if got := cfg.values[name]; got != value {
+			t.Errorf("option %s: got %+v, want %+v", name, got, value)
+		}
+	}
+
+	options := cfg.options()
+	if !options.Strict || !options.EnableTranslator || options.Merge || options.OutputDir != "from/env" || options.Package != "flagpkg" {
+		t.Errorf("unexpected options %+v", options)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	for _, spec := range optionSpecs {
+		t.Setenv(spec.Env, "")
+	}
+
+	tests := []struct {
+		content string
+		env     string
+		error   string
+	}{
+		{content: "outptu: types\n", error: `unknown option "outptu"`},
+		{content: "strict: maybe\n", error: `invalid value "maybe" for option strict`},
+		{env: "yes please", error: "from env GOCTL_VALIDATE_MERGE"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if tt.content != "" {
+			if err := os.WriteFile(filepath.Join(dir, configFileName), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		t.Setenv("GOCTL_VALIDATE_MERGE", tt.env)
+
+		_, err := loadConfig(newOptionFlags(t), dir)
+		if err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("got error %v, want %q", err, tt.error)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package types
+
+import "github.com/go-playground/validator/v10"
+
+// ValidateStruct 修改密码的跨字段规则，由生成的validate.go注册到共享的validator实例
+func (r PasswordChangeReq) ValidateStruct(sl validator.StructLevel) {
+	if r.NewPassword == r.OldPassword {
+		sl.ReportError(r.NewPassword, "NewPassword", "NewPassword", "nefield", "OldPassword")
+	}
+	if r.ConfirmPassword != r.NewPassword {
+		sl.ReportError(r.ConfirmPassword, "ConfirmPassword", "ConfirmPassword", "eqfield", "NewPassword")
+	}
+}
@@ -38,7 +38,7 @@ type UserQueryReq struct {
 	Page     int    `json:"page" validate:"required,min=1"`
 	PageSize int    `json:"pageSize" validate:"required,min=1,max=100"`
 	Keyword  string `json:"keyword,omitempty" validate:"omitempty,min=1,max=50"`
-	Status   int    `json:"status" validate:"required,oneof=0 1"`
+	Status   int    `json:"status" validate:"oneof=0 1"`
 	UserType int    `json:"userType" validate:"required,oneof=1 2 3"`
 }
 
@@ -49,7 +49,7 @@ type UserRegisterReq struct {
 	Phone    string `json:"phone" validate:"required,len=11,numeric"`
 	Nickname string `json:"nickname" validate:"required,min=1,max=30"`
 	Age      int    `json:"age" validate:"required,min=1,max=150"`
-	Gender   int    `json:"gender" validate:"required,oneof=0 1 2"`
+	Gender   int    `json:"gender" validate:"oneof=0 1 2"`
 }
 
 type UserUpdateReq struct {
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/ast"
+	apiparser "github.com/zeromicro/go-zero/tools/goctl/pkg/parser/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// runGen gen子命令：自行解析API文件构造插件上下文，
+// 与goctl调用插件时得到的输入相同，可以在go generate、Makefile和测试中使用
+func runGen(args []string) {
+	fs := flag.NewFlagSet("goctl-validate gen", flag.ExitOnError)
+	apiFile := fs.String("api", "", "the api file")
+	dir := fs.String("dir", ".", "the target directory")
+	style := fs.String("style", "", "the file naming format, see goctl --style")
+	bindOptionFlags(fs)
+	fs.Parse(args)
+
+	if *apiFile == "" {
+		fmt.Println("goctl-validate: missing -api")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	p, err := newPlugin(*apiFile, *dir, *style)
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+
+	generate(fs, p)
+}
+
+// newPlugin 按照goctl调用插件的方式构造插件上下文：路径转换为绝对路径，并用goctl的解析器解析API文件
+func newPlugin(apiFile, dir, style string) (*plugin.Plugin, error) {
+	apiFilePath, err := filepath.Abs(apiFile)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkImportCycle(apiFilePath); err != nil {
+		return nil, err
+	}
+	api, err := parser.Parse(apiFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin.Plugin{
+		Api:         api,
+		ApiFilePath: apiFilePath,
+		Style:       style,
+		Dir:         absDir,
+	}, nil
+}
+
+// checkImportCycle 沿着import查找循环引用。goctl的解析器只报告发现循环的那一行，
+// 这里输出从主API文件开始的完整引用链，例如 a.api -> b.api -> c.api -> b.api。
+// 文件不存在、语法错误等问题留给goctl的解析器报告
+func checkImportCycle(apiFilePath string) error {
+	baseDir := filepath.Dir(apiFilePath)
+	var stack []string
+	done := make(map[string]bool)
+
+	var visit func(path string) error
+	visit = func(path string) error {
+		for _, importing := range stack {
+			if importing != path {
+				continue
+			}
+			var chain []string
+			for _, file := range append(append([]string{}, stack...), path) {
+				if rel, err := filepath.Rel(baseDir, file); err == nil {
+					file = rel
+				}
+				chain = append(chain, file)
+			}
+			return fmt.Errorf("import cycle not allowed: %s", strings.Join(chain, " -> "))
+		}
+		if done[path] {
+			return nil
+		}
+
+		stack = append(stack, path)
+		for _, imported := range apiImports(path) {
+			if err := visit(imported); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		done[path] = true
+		return nil
+	}
+	return visit(apiFilePath)
+}
+
+// apiImports 解析API文件中import的文件路径，相对路径基于该文件所在目录，无法解析时返回空
+func apiImports(apiFilePath string) []string {
+	// goctl的扫描器遇到不存在或为空的文件时直接退出进程
+	data, err := os.ReadFile(apiFilePath)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	p := apiparser.New(apiFilePath, data)
+	tree := p.Parse()
+	if tree == nil || p.CheckErrors() != nil {
+		return nil
+	}
+
+	var values []*ast.TokenNode
+	for _, stmt := range tree.Stmts {
+		switch stmt := stmt.(type) {
+		case *ast.ImportLiteralStmt:
+			values = append(values, stmt.Value)
+		case *ast.ImportGroupStmt:
+			values = append(values, stmt.Values...)
+		}
+	}
+
+	var imports []string
+	for _, value := range values {
+		path := strings.Trim(value.Token.Text, `"`)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(apiFilePath), path)
+		}
+		imports = append(imports, path)
+	}
+	return imports
+}
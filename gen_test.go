@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAPIFiles 在临时目录中创建API文件，imports为每个文件import的文件
+func writeAPIFiles(t *testing.T, imports map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, imported := range imports {
+		content := "syntax = \"v1\"\n\n"
+		if len(imported) > 0 {
+			content += "import (\n"
+			for _, file := range imported {
+				content += "\t\"" + file + "\"\n"
+			}
+			content += ")\n\n"
+		}
+		typeName := strings.ToUpper(strings.TrimSuffix(filepath.Base(name), ".api"))
+		content += "type " + typeName + " {\n\tName string `json:\"name\"`\n}\n"
+
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheckImportCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		imports map[string][]string
+		error   string // 为空表示没有循环
+	}{
+		{
+			name:    "cycle",
+			imports: map[string][]string{"a.api": {"b.api"}, "b.api": {"c.api"}, "c.api": {"b.api"}},
+			error:   "import cycle not allowed: a.api -> b.api -> c.api -> b.api",
+		},
+		{
+			name:    "self import",
+			imports: map[string][]string{"a.api": {"a.api"}},
+			error:   "import cycle not allowed: a.api -> a.api",
+		},
+		{
+			name:    "subdirectory",
+			imports: map[string][]string{"a.api": {"types/b.api"}, "types/b.api": {"../a.api"}},
+			error:   "import cycle not allowed: a.api -> types/b.api -> a.api",
+		},
+		{
+			name:    "diamond",
+			imports: map[string][]string{"a.api": {"b.api", "c.api"}, "b.api": {"d.api"}, "c.api": {"d.api"}, "d.api": nil},
+		},
+		{
+			name:    "missing import",
+			imports: map[string][]string{"a.api": {"missing.api"}},
+		},
+	}
+
+	for _, tt := range tests {
+		dir := writeAPIFiles(t, tt.imports)
+		err := checkImportCycle(filepath.Join(dir, "a.api"))
+		switch {
+		case tt.error == "" && err != nil:
+			t.Errorf("%s: got error %v, want none", tt.name, err)
+		case tt.error != "" && (err == nil || err.Error() != tt.error):
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.error)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldScope 解析字段引用时可见的字段集合
+type fieldScope struct {
+	Name   string // 用于错误提示的作用域名称，例如 PasswordChangeReq 或 CreateOrderReq.Address
+	Fields []ValidateField
+}
+
+// checkCrossFieldRefs 检查eqfield、required_if等规则引用的字段是否存在、类型是否匹配
+// 普通规则相对于字段所在的结构体解析，*cs*规则相对于顶层结构体解析
+func (g *typeGraph) checkCrossFieldRefs() []lintIssue {
+	var issues []lintIssue
+	for _, name := range g.order {
+		validateStruct := g.structs[name]
+		issues = append(issues, g.checkScopeRefs(validateStruct, fieldScope{name, validateStruct.Fields}, name)...)
+	}
+	return issues
+}
+
+// checkScopeRefs 检查作用域内每个字段的引用，内联结构体的字段以内联结构体为作用域
+func (g *typeGraph) checkScopeRefs(top *ValidateStruct, scope fieldScope, namespace string) []lintIssue {
+	var issues []lintIssue
+	for _, field := range scope.Fields {
+		fieldNamespace := namespace + "." + field.Name
+		if field.ValidateRule != "" && field.ValidateRule != "-" {
+			issues = append(issues, g.checkFieldRefs(top, scope, fieldNamespace, field)...)
+		}
+		if len(field.Fields) > 0 {
+			inline := fieldScope{Name: fieldNamespace, Fields: field.Fields}
+			issues = append(issues, g.checkScopeRefs(top, inline, fieldNamespace)...)
+		}
+	}
+	return issues
+}
+
+// checkFieldRefs 检查单个字段validate标签中的字段引用
+func (g *typeGraph) checkFieldRefs(top *ValidateStruct, scope fieldScope, namespace string, field ValidateField) []lintIssue {
+	var issues []lintIssue
+	for _, token := range strings.Split(field.ValidateRule, ",") {
+		for _, alt := range strings.Split(token, "|") {
+			name, param := splitRule(alt)
+			rule, ok := knownRules[name]
+			if !ok || param == "" {
+				continue
+			}
+
+			// *cs* 规则相对于顶层结构体解析
+			refScopes := []fieldScope{scope}
+			if strings.Contains(name, "csfield") {
+				refScopes = g.topScopes(top)
+			}
+
+			switch rule.Param {
+			case paramField:
+				ref, msg := g.resolveRef(refScopes, param)
+				if ref != nil {
+					msg = compareFieldTypes(name, field.Type, ref)
+				}
+				if msg != "" {
+					issues = append(issues, newIssue(field, namespace, "'%s': %s", alt, msg))
+				}
+			case paramFields:
+				for _, refName := range strings.Fields(param) {
+					if _, msg := g.resolveRef(refScopes, refName); msg != "" {
+						issues = append(issues, newIssue(field, namespace, "'%s': %s", alt, msg))
+					}
+				}
+			case paramFieldValue:
+				values := strings.Fields(param)
+				for i := 0; i+1 < len(values); i += 2 {
+					ref, msg := g.resolveRef(refScopes, values[i])
+					if ref != nil {
+						msg = checkRefValue(values[i], values[i+1], ref)
+					}
+					if msg != "" {
+						issues = append(issues, newIssue(field, namespace, "'%s': %s", alt, msg))
+					}
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// topScopes *cs*规则可能的顶层结构体：声明字段的结构体本身，以及直接引用它的结构体
+func (g *typeGraph) topScopes(top *ValidateStruct) []fieldScope {
+	scopes := []fieldScope{{top.Name, top.Fields}}
+	for _, name := range g.order {
+		parent := g.structs[name]
+		if parent.Name == top.Name {
+			continue
+		}
+
+		walkFields(parent.Fields, name, func(_ string, field ValidateField) {
+			if ref := g.fieldRef(field); ref != nil && g.resolve(ref.Target) == top {
+				scopes = append(scopes, fieldScope{parent.Name, parent.Fields})
+			}
+		})
+	}
+	return scopes
+}
+
+// resolveRef 在任一作用域中解析 Field 或 Inner.Field 形式的字段引用，
+// 找不到时返回错误描述。带下标的引用（例如 Items[0].Name）无法静态解析，字段和错误描述都返回空
+func (g *typeGraph) resolveRef(scopes []fieldScope, ref string) (*ValidateField, string) {
+	if strings.ContainsAny(ref, "[]") {
+		return nil, ""
+	}
+
+	var names []string
+	for _, scope := range scopes {
+		if field := g.lookupPath(scope.Fields, strings.Split(ref, ".")); field != nil {
+			return field, ""
+		}
+		names = append(names, scope.Name)
+	}
+	return nil, fmt.Sprintf("field '%s' not found in %s", ref, strings.Join(names, " or "))
+}
+
+// lookupPath 按路径逐级查找字段，结构体类型的字段继续在其字段中查找
+func (g *typeGraph) lookupPath(fields []ValidateField, path []string) *ValidateField {
+	field := g.lookupField(fields, path[0], make(map[string]bool))
+	if field == nil || len(path) == 1 {
+		return field
+	}
+
+	if len(field.Fields) > 0 {
+		return g.lookupPath(field.Fields, path[1:])
+	}
+	if t := field.Type.Deref(); t != nil && t.Kind == KindNamed {
+		if target := g.resolve(t.Name); target != nil {
+			return g.lookupPath(target.Fields, path[1:])
+		}
+	}
+	return nil
+}
+
+// lookupField 查找字段，包括内嵌结构体提升上来的字段
+func (g *typeGraph) lookupField(fields []ValidateField, name string, visited map[string]bool) *ValidateField {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+
+	for _, field := range fields {
+		if !field.Embedded {
+			continue
+		}
+		t := field.Type.Deref()
+		if t == nil || visited[t.Name] {
+			continue
+		}
+		visited[t.Name] = true
+		if target := g.resolve(t.Name); target != nil {
+			if found := g.lookupField(target.Fields, name, visited); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// compareFieldTypes 检查字段比较规则两侧的类型，validator在类型种类不同时比较结果永远为false
+func compareFieldTypes(name string, fieldType *FieldType, ref *ValidateField) string {
+	if strings.HasPrefix(name, "field") {
+		// fieldcontains、fieldexcludes要求两侧都是字符串
+		if classOf(ref.Type) != classString && classOf(ref.Type) != classAny {
+			return fmt.Sprintf("referenced field %s is %s, expected string", ref.Name, ref.Type)
+		}
+		return ""
+	}
+
+	left, right := fieldType.Deref(), ref.Type.Deref()
+	if left == nil || right == nil || classOf(left) == classAny || classOf(right) == classAny {
+		return ""
+	}
+	if left.String() != right.String() {
+		return fmt.Sprintf("compares %s with field %s of type %s, the types never match", fieldType, ref.Name, ref.Type)
+	}
+	return ""
+}
+
+// checkRefValue 检查required_if等规则中的取值能否按被引用字段的类型解析
+func checkRefValue(refName, value string, ref *ValidateField) string {
+	switch classOf(ref.Type) {
+	case classNumber:
+		if msg := checkNumberParam(refName, value, ref.Type); msg != "" {
+			return fmt.Sprintf("value %q is not a valid number for field %s (%s)", value, refName, ref.Type)
+		}
+	case classBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Sprintf("value %q is not a valid bool for field %s", value, refName)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCrossFieldRefs(t *testing.T) {
+	structs := []ValidateStruct{
+		{Name: "Address", Fields: []ValidateField{newField("City", "string", "required")}},
+		{Name: "Contact", Fields: []ValidateField{newField("Phone", "string", "")}},
+		{Name: "PasswordReq", Fields: []ValidateField{
+			newField("Password", "string", "required"),
+			newField("Confirm", "string", "eqfield=Password"),
+			newField("Typo", "string", "eqfield=Passwd"),
+			newField("Age", "int", "gtfield=Password"),
+			newField("Type", "int", ""),
+			newField("Code", "string", "required_if=Type 2"),
+			newField("Reason", "string", "required_if=Type x"),
+			newField("Addr", "Address", ""),
+			newField("City", "string", "eqfield=Addr.City"),
+			{Name: "Contact", Type: &FieldType{Kind: KindNamed, Name: "Contact"}, Embedded: true},
+			newField("Mobile", "string", "nefield=Phone"),
+			newField("First", "string", "eqfield=Items[0].Name"),
+			newField("Kind", "string", "required_if=Items[0].Type 1"),
+			newField("Email", "string", "required_without=Phone Fax"),
+		}},
+		{Name: "Order", Fields: []ValidateField{
+			newField("OwnerID", "string", ""),
+			newField("Items", "[]Item", "dive"),
+		}},
+		{Name: "Item", Fields: []ValidateField{
+			newField("Owner", "string", "necsfield=OwnerID"),
+			newField("Buyer", "string", "necsfield=BuyerID"),
+		}},
+	}
+
+	want := []string{
+		"PasswordReq.Typo: 'eqfield=Passwd': field 'Passwd' not found in PasswordReq",
+		"PasswordReq.Age: 'gtfield=Password': compares int with field Password of type string, the types never match",
+		"PasswordReq.Reason: 'required_if=Type x': value \"x\" is not a valid number for field Type (int)",
+		"PasswordReq.Email: 'required_without=Phone Fax': field 'Fax' not found in PasswordReq",
+		"Item.Buyer: 'necsfield=BuyerID': field 'BuyerID' not found in Item or Order",
+	}
+
+	issues := newTypeGraph(structs, nil).checkCrossFieldRefs()
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues %v, want %d", len(issues), issues, len(want))
+	}
+	for i, issue := range issues {
+		if got := issue.String(); !strings.HasPrefix(got, want[i]) {
+			t.Errorf("issue %d: got %q, want %q", i, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// registerRuleMethods validator中注册规则的方法，第一个参数为规则名
+var registerRuleMethods = map[string]bool{
+	"RegisterValidation":    true,
+	"RegisterValidationCtx": true,
+	"RegisterAlias":         true,
+}
+
+// customRules 输出目录中手写代码注册的自定义规则
+type customRules struct {
+	File  string            // 自定义规则文件名，例如 validate_custom.go，用于提示
+	Names map[string]string // 规则名 -> 注册位置
+}
+
+// addRules 记录文件中以字符串字面量注册的规则名，例如 validate.RegisterValidation("mobile", ...)
+func (d *existingDecls) addRules(fset *token.FileSet, file *ast.File) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !registerRuleMethods[sel.Sel.Name] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		if name, err := strconv.Unquote(lit.Value); err == nil && name != "" {
+			d.rules[name] = fset.Position(lit.Pos()).String()
+		}
+		return true
+	})
+}
+
+// checkCustomRule 检查不是内置规则的规则名：已经注册的自定义规则合法，
+// 与内置规则相近的视为拼写错误，其余的提示在自定义规则文件中注册
+func checkCustomRule(field ValidateField, namespace, name string, custom customRules) []lintIssue {
+	if _, ok := custom.Names[name]; ok {
+		return nil
+	}
+	if suggestion := suggestRule(name); suggestion != "" {
+		return []lintIssue{newIssue(field, namespace, "unknown rule '%s', did you mean '%s'?", name, suggestion)}
+	}
+
+	issue := newIssue(field, namespace, "unknown rule '%s' is not registered, register it in %s or validation will panic at runtime", name, custom.File)
+	issue.Warning = true
+	return []lintIssue{issue}
+}
+
+// structHookMethod 结构体级别验证方法的名称，签名为 func(sl validator.StructLevel)
+const structHookMethod = "ValidateStruct"
+
+// validatorImportPath validator的导入路径，用于识别StructLevel参数
+const validatorImportPath = "github.com/go-playground/validator/v10"
+
+// addHooks 记录签名为 ValidateStruct(sl validator.StructLevel) 的方法的接收者类型
+func (d *existingDecls) addHooks(fset *token.FileSet, file *ast.File) {
+	pkgName := ""
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err != nil || path != validatorImportPath {
+			continue
+		}
+		pkgName = "validator"
+		if spec.Name != nil {
+			pkgName = spec.Name.Name
+		}
+	}
+	if pkgName == "" || pkgName == "_" || pkgName == "." {
+		return
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 || fn.Name.Name != structHookMethod {
+			continue
+		}
+		if fn.Type.Results != nil || fn.Type.Params.NumFields() != 1 {
+			continue
+		}
+		sel, ok := fn.Type.Params.List[0].Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "StructLevel" {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != pkgName {
+			continue
+		}
+		if recv := receiverTypeName(fn.Recv.List[0].Type); recv != "" {
+			d.hooks[recv] = fset.Position(fn.Pos()).String()
+		}
+	}
+}
+
+// structHooks 返回实现了ValidateStruct方法的结构体，包括API中的结构体以及合并模式下其他入口的结构体，按名称排序，
+// 生成的代码通过RegisterStructValidation注册，validator验证到这些类型时都会调用
+func (g *typeGraph) structHooks(validateStructs []ValidateStruct) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if _, ok := g.hooks[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range g.order {
+		add(name)
+	}
+	for _, validateStruct := range validateStructs {
+		add(validateStruct.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	goparser "go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAddHooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		hooks  []string // 识别出的接收者类型
+	}{
+		{
+			name: "pointer and value receivers",
+			source: `package types
+
+import "github.com/go-playground/validator/v10"
+
+func (r *PasswordReq) ValidateStruct(sl validator.StructLevel) {}
+func (r Order) ValidateStruct(sl validator.StructLevel) {}
+`,
+			hooks: []string{"Order", "PasswordReq"},
+		},
+		{
+			name: "renamed import",
+			source: `package types
+
+import v "github.com/go-playground/validator/v10"
+
+func (r *PasswordReq) ValidateStruct(sl v.StructLevel) {}
+`,
+			hooks: []string{"PasswordReq"},
+		},
+		{
+			name: "wrong signatures",
+			source: `package types
+
+import "github.com/go-playground/validator/v10"
+
+func (r *A) ValidateStruct(sl validator.StructLevel) error { return nil }
+func (r *B) ValidateStruct() {}
+func (r *C) ValidateStruct(sl validator.FieldLevel) {}
+func (r *D) ValidateStruct(sl other.StructLevel) {}
+func (r *E) Check(sl validator.StructLevel) {}
+func ValidateStruct(sl validator.StructLevel) {}
+`,
+		},
+		{
+			name: "validator not imported",
+			source: `package types
+
+func (r *PasswordReq) ValidateStruct(sl validator.StructLevel) {}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		fset := token.NewFileSet()
+		file, err := goparser.ParseFile(fset, "password.go", tt.source, goparser.SkipObjectResolution)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		decls := &existingDecls{hooks: make(map[string]string)}
+		decls.addHooks(fset, file)
+
+		var hooks []string
+		for name := range decls.hooks {
+			hooks = append(hooks, name)
+		}
+		sort.Strings(hooks)
+		if !reflect.DeepEqual(hooks, tt.hooks) {
+			t.Errorf("%s: got hooks %v, want %v", tt.name, hooks, tt.hooks)
+		}
+	}
+}
+
+func TestStructHooks(t *testing.T) {
+	hooks := map[string]string{"Plain": "password.go:5:1", "Address": "password.go:9:1", "Unknown": "password.go:13:1"}
+	g := newTypeGraph(graphStructs(), hooks)
+
+	// 没有validate标签但有ValidateStruct方法的结构体同样生成验证方法
+	var names []string
+	for _, validateStruct := range g.selectValidateStructs() {
+		names = append(names, validateStruct.Name)
+	}
+	if want := []string{"Address", "User", "Order", "OrderItem", "Plain", "Base", "Inline"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("selected %v, want %v", names, want)
+	}
+
+	// 只注册API中存在的结构体
+	if got, want := g.structHooks(nil), []string{"Address", "Plain"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("struct hooks %v, want %v", got, want)
+	}
+}
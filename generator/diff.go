@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext 差异块前后保留的上下文行数
+const diffContext = 3
+
+// diffOp 行级差异：' ' 表示相同，'-' 表示删除，'+' 表示新增
+type diffOp struct {
+	Kind byte
+	Text string
+}
+
+// unifiedDiff 生成两个文本的统一diff格式差异
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// oldPos[k]、newPos[k] 为第k个操作之前已经消耗的行数
+	oldPos := make([]int, len(ops)+1)
+	newPos := make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldPos[k+1], newPos[k+1] = oldPos[k], newPos[k]
+		if op.Kind != '+' {
+			oldPos[k+1]++
+		}
+		if op.Kind != '-' {
+			newPos[k+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		// 查找下一个改动
+		first := start
+		for first < len(ops) && ops[first].Kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// 相邻改动之间的相同行不超过两倍上下文时合并为同一个差异块
+		last := first
+		for k := first; k < len(ops) && k-last <= 2*diffContext; k++ {
+			if ops[k].Kind != ' ' {
+				last = k
+			}
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(last+diffContext+1, len(ops))
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldPos[hunkStart], oldPos[hunkEnd]), hunkRange(newPos[hunkStart], newPos[hunkEnd]))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			b.WriteByte(op.Kind)
+			b.WriteString(op.Text)
+			b.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return b.String()
+}
+
+// hunkRange 差异块头部的行范围，例如 3,5；没有行时起始行为前一行
+func hunkRange(from, to int) string {
+	if from == to {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, to-from)
+}
+
+// diffLines 基于最长公共子序列计算两组行之间的差异
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines 按行拆分文本，空文本没有任何行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
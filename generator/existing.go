@@ -0,0 +1,362 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// existingDecls 输出目录中已有的手写Go文件声明的包级标识符、方法、注册的自定义规则以及结构体级别的验证方法，
+// 插件生成的文件（带有生成标记）不计算在内，translator_custom.go、validate_custom.go 只记录注册的规则和验证方法
+type existingDecls struct {
+	dir                  string
+	pkg                  string
+	customTranslatorFile string                       // 自定义翻译文件的路径
+	customValidateFile   string                       // 自定义规则文件的路径
+	idents               map[string]string            // 包级标识符 -> 声明位置
+	methods              map[string]map[string]string // 接收者类型 -> 方法名 -> 声明位置
+	rules                map[string]string            // 注册的自定义规则名 -> 注册位置
+	hooks                map[string]string            // 实现了ValidateStruct方法的类型 -> 声明位置
+}
+
+// scanExisting 解析输出目录中已有的手写代码，用于检查自定义规则以及与生成代码的冲突
+func (g *ValidateGenerator) scanExisting(outputDir string) (*existingDecls, error) {
+	pkg, err := g.packageName(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	customTranslatorFile, err := g.outputFileName(outputDir, "translator_custom")
+	if err != nil {
+		return nil, err
+	}
+	customValidateFile, err := g.outputFileName(outputDir, "validate_custom")
+	if err != nil {
+		return nil, err
+	}
+
+	// 自定义文件由插件创建但可以手动修改，其中的声明与生成的代码配套，不参与冲突检查
+	customFiles := map[string]bool{
+		filepath.Base(customTranslatorFile): true,
+		filepath.Base(customValidateFile):   true,
+	}
+	targets, err := g.outputTargets(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	decls, err := g.scanExistingDecls(outputDir, pkg, customFiles, targets)
+	if err != nil {
+		return nil, err
+	}
+	decls.customTranslatorFile = customTranslatorFile
+	decls.customValidateFile = customValidateFile
+	return decls, nil
+}
+
+// outputTargets 本次会写入的生成文件名。没有生成标记的同名文件（例如旧版本生成的validate.go）
+// 会在写入时被拒绝或者使用 -force 覆盖，其中的声明不会保留，因此不参与冲突检查
+func (g *ValidateGenerator) outputTargets(outputDir string) (map[string]bool, error) {
+	names := []string{"validate"}
+	if g.options.EnableTranslator {
+		names = append(names, "translator")
+	}
+
+	targets := make(map[string]bool)
+	for _, name := range names {
+		path, err := g.outputFileName(outputDir, name)
+		if err != nil {
+			return nil, err
+		}
+		targets[filepath.Base(path)] = true
+	}
+	if g.options.Static {
+		path, err := g.testFileName(outputDir, "validate_bench")
+		if err != nil {
+			return nil, err
+		}
+		targets[filepath.Base(path)] = true
+	}
+	return targets, nil
+}
+
+// customRules 手写代码中注册的自定义规则
+func (d *existingDecls) customRules() customRules {
+	return customRules{File: filepath.Base(d.customValidateFile), Names: d.rules}
+}
+
+// scanExistingDecls 使用go/parser解析输出目录中属于pkg的Go文件，rulesOnly中的文件只记录注册的规则和验证方法，
+// skip中的文件不解析，测试文件只参与冲突检查，其中的规则和验证方法在生成的代码中无法使用
+func (g *ValidateGenerator) scanExistingDecls(dir, pkg string, rulesOnly, skip map[string]bool) (*existingDecls, error) {
+	decls := &existingDecls{
+		dir:     dir,
+		pkg:     pkg,
+		idents:  make(map[string]string),
+		methods: make(map[string]map[string]string),
+		rules:   make(map[string]string),
+		hooks:   make(map[string]string),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return decls, nil
+		}
+		return nil, fmt.Errorf("failed to read output directory %s: %v", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || skip[name] {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		if hasGeneratedMarker(string(content)) {
+			continue
+		}
+
+		file, err := goparser.ParseFile(fset, path, content, goparser.SkipObjectResolution)
+		if err != nil {
+			g.warnf("failed to parse %s, declarations in it are not checked: %v", path, err)
+			continue
+		}
+		// 外部测试包（package types_test）不会与生成的代码冲突
+		if file.Name.Name != pkg {
+			continue
+		}
+		if !strings.HasSuffix(name, "_test.go") {
+			decls.addRules(fset, file)
+			decls.addHooks(fset, file)
+		}
+		if !rulesOnly[name] {
+			decls.add(fset, file)
+		}
+	}
+	return decls, nil
+}
+
+// add 记录文件中的包级声明
+func (d *existingDecls) add(fset *token.FileSet, file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			pos := fset.Position(decl.Pos()).String()
+			if decl.Recv == nil {
+				if decl.Name.Name != "init" {
+					d.addIdent(decl.Name, pos)
+				}
+				continue
+			}
+			if len(decl.Recv.List) == 0 {
+				continue
+			}
+			recv := receiverTypeName(decl.Recv.List[0].Type)
+			if recv == "" {
+				continue
+			}
+			if d.methods[recv] == nil {
+				d.methods[recv] = make(map[string]string)
+			}
+			d.methods[recv][decl.Name.Name] = pos
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						d.addIdent(name, fset.Position(name.Pos()).String())
+					}
+				case *ast.TypeSpec:
+					d.addIdent(spec.Name, fset.Position(spec.Name.Pos()).String())
+				}
+			}
+		}
+	}
+}
+
+// addIdent 记录包级标识符，空白标识符不会冲突
+func (d *existingDecls) addIdent(name *ast.Ident, pos string) {
+	if name.Name != "_" {
+		d.idents[name.Name] = pos
+	}
+}
+
+// findMethod 查找类型上已经声明的同名方法，返回方法名和声明位置
+func (d *existingDecls) findMethod(typeName string, methods []string) (string, string, bool) {
+	for _, method := range methods {
+		if pos, ok := d.methods[typeName][method]; ok {
+			return method, pos, true
+		}
+	}
+	return "", "", false
+}
+
+// receiverTypeName 方法接收者的类型名，例如 *Req、Req、*List[T] 分别返回 Req、Req、List
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// generatedIdents 生成的代码声明的包级标识符，值为修改该名称的选项，不能修改的为空，
+// createTranslator、createValidate 表示本次是否会创建对应的自定义文件
+func (g *ValidateGenerator) generatedIdents(createTranslator, createValidate bool) map[string]string {
+	idents := map[string]string{
+		g.options.ValidatorVar: "validator-var",
+		"JSONFields":           "",
+		"jsonFields":           "",
+		"nestedJSONFields":     "",
+	}
+	if createValidate {
+		idents["registerCustomValidations"] = ""
+	}
+	if g.options.EnableTranslator {
+		idents[g.options.TranslatorVar] = "translator-var"
+		idents["registerCustomTranslations"] = ""
+		idents["getCustomTranslationRegister"] = ""
+		idents["Translate"] = ""
+		idents["TranslateErrors"] = ""
+		if createTranslator {
+			idents["registerCustomTranslationsImpl"] = ""
+		}
+	}
+	if g.options.Static {
+		for _, re := range staticRegexps {
+			idents[re.Name] = ""
+		}
+	}
+	return idents
+}
+
+// checkExisting 检查生成的代码与输出目录中手写代码的冲突：
+// 已经手写了Validate、ValidateCtx等同名方法的类型跳过生成并返回类型名，包级标识符或字段名冲突时返回错误
+func (g *ValidateGenerator) checkExisting(decls *existingDecls, validateStructs []ValidateStruct) ([]ValidateStruct, []string, error) {
+	pkg := decls.pkg
+
+	var conflicts []string
+	for ident, option := range g.generatedIdents(!fileExists(decls.customTranslatorFile), !fileExists(decls.customValidateFile)) {
+		pos, ok := decls.idents[ident]
+		if !ok {
+			continue
+		}
+		if option != "" {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s is already declared in package %s, use -%s to choose another name", pos, ident, pkg, option))
+		} else {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s is already declared in package %s and conflicts with the generated code", pos, ident, pkg))
+		}
+	}
+
+	var result []ValidateStruct
+	var handwritten []string
+	methods := g.options.methods()
+	for _, validateStruct := range validateStructs {
+		if method, pos, ok := decls.findMethod(validateStruct.Name, methods); ok {
+			logf("%s already has a %s method at %s, skipped", validateStruct.Name, method, pos)
+			handwritten = append(handwritten, validateStruct.Name)
+			continue
+		}
+		for _, field := range validateStruct.Fields {
+			for _, method := range methods {
+				if field.Name == method {
+					conflicts = append(conflicts, fmt.Sprintf("%s has a field named %s, use -method to choose another method name", validateStruct.Name, field.Name))
+				}
+			}
+		}
+		result = append(result, validateStruct)
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		for _, conflict := range conflicts {
+			logf("error - %s", conflict)
+		}
+		return nil, nil, fmt.Errorf("found %d conflicts with existing code in %s", len(conflicts), decls.dir)
+	}
+	return result, handwritten, nil
+}
+
+// reservedNames 生成的代码导入的包名和使用的局部变量名，包级变量不能与之同名
+var reservedNames = map[string]bool{
+	"validator": true,
+	"errors":    true,
+	"fmt":       true,
+	"zhongwen":  true,
+	"ut":        true,
+	"zh":        true,
+	"zw":        true,
+	"uni":       true,
+	"regexp":    true,
+	"utf8":      true,
+	"testing":   true,
+	"sample":    true,
+	"b":         true,
+	"i":         true,
+	"context":   true,
+	"ctx":       true,
+	"json":      true,
+	"reflect":   true,
+	"strconv":   true,
+	"strings":   true,
+	"fields":    true,
+}
+
+// ctxMethod 带context的验证方法名，例如 ValidateCtx
+func (o *Options) ctxMethod() string {
+	return o.Method + "Ctx"
+}
+
+// methods 为每个类型生成的所有方法名
+func (o *Options) methods() []string {
+	return []string{o.Method, o.ctxMethod(), o.Method + "Partial", o.Method + "Except"}
+}
+
+// checkNames 检查可配置的名称是否为合法的Go标识符，接收者名称不能遮蔽生成的包级变量
+func (o *Options) checkNames() error {
+	names := []struct{ option, value string }{
+		{"receiver", o.Receiver},
+		{"method", o.Method},
+		{"validator-var", o.ValidatorVar},
+		{"translator-var", o.TranslatorVar},
+	}
+	for _, name := range names {
+		if !token.IsIdentifier(name.value) || name.value == "_" {
+			return fmt.Errorf("invalid -%s %q: not a valid Go identifier", name.option, name.value)
+		}
+	}
+	for _, name := range names {
+		if name.option != "method" && reservedNames[name.value] {
+			return fmt.Errorf("invalid -%s %q: conflicts with a name used by the generated code", name.option, name.value)
+		}
+	}
+	if o.Method == structHookMethod {
+		return fmt.Errorf("invalid -method %q: reserved for hand-written struct-level validation", o.Method)
+	}
+	if o.ValidatorVar == o.TranslatorVar {
+		return fmt.Errorf("-validator-var and -translator-var must be different, both are %q", o.ValidatorVar)
+	}
+	if o.Receiver == o.ValidatorVar || o.Receiver == o.TranslatorVar {
+		return fmt.Errorf("-receiver %q shadows the generated package variable of the same name", o.Receiver)
+	}
+	return nil
+}
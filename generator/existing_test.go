@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// legacyFiles 没有生成标记的旧版本输出，以及goctl生成的types.go和可编辑的translator_custom.go
+var legacyFiles = map[string]string{
+	"types.go": `package types
+
+type LoginReq struct {
+	Username string ` + "`json:\"username\" validate:\"required\"`" + `
+}
+`,
+	"validate.go": `package types
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// 共享的validator实例
+var validate = validator.New()
+
+// Validate 验证LoginReq结构体
+func (r *LoginReq) Validate() error {
+	return validate.Struct(r)
+}
+`,
+	"translator.go": `package types
+
+import (
+	"fmt"
+	"github.com/go-playground/locales/zh_Hans_CN"
+	"github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/translations/zh"
+)
+
+// 翻译器实例
+var translator ut.Translator
+
+func init() {
+	zhCN := zh_Hans_CN.New()
+	uni := ut.New(zhCN, zhCN)
+	translator, _ = uni.GetTranslator("zh_Hans_CN")
+	zh.RegisterDefaultTranslations(validate, translator)
+	registerCustomTranslations()
+}
+
+func registerCustomTranslations() {
+	if customRegister := getCustomTranslationRegister(); customRegister != nil {
+		customRegister(validate, translator)
+	}
+}
+
+var getCustomTranslationRegister = func() func(*validator.Validate, ut.Translator) {
+	return nil
+}
+
+func TranslateError(err error) error {
+	return fmt.Errorf("%v", err)
+}
+
+func TranslateErrors(err error) []string {
+	return []string{err.Error()}
+}
+`,
+	"translator_custom.go": `package types
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	getCustomTranslationRegister = func() func(*validator.Validate, ut.Translator) {
+		return registerCustomTranslationsImpl
+	}
+}
+
+func registerCustomTranslationsImpl(validate *validator.Validate, translator ut.Translator) {
+}
+`,
+}
+
+// newLegacyProject 创建带有旧版本输出的项目，返回插件上下文和输出目录
+func newLegacyProject(t *testing.T) (*plugin.Plugin, string) {
+	t.Helper()
+	dir := t.TempDir()
+	apiFile := filepath.Join(dir, "user.api")
+	api := "syntax = \"v1\"\n\ntype LoginReq {\n\tUsername string `json:\"username\" validate:\"required\"`\n}\n"
+	writeTestFile(t, apiFile, api)
+
+	outputDir := filepath.Join(dir, "internal", "types")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range legacyFiles {
+		writeTestFile(t, filepath.Join(outputDir, name), content)
+	}
+
+	spec, err := parser.Parse(apiFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &plugin.Plugin{Api: spec, ApiFilePath: apiFile, Dir: dir}, outputDir
+}
+
+// TestGenerateUpgradeFromLegacyOutput 旧版本生成的validate.go、translator.go没有生成标记，
+// 不能被当作手写代码：不会因为其中的Validate方法跳过类型，也不会报告标识符冲突
+func TestGenerateUpgradeFromLegacyOutput(t *testing.T) {
+	logOutput = io.Discard
+	defer func() { logOutput = os.Stdout }()
+
+	// 没有 -force 时拒绝覆盖，提示使用 -force
+	p, outputDir := newLegacyProject(t)
+	err := NewValidateGenerator(p, &Options{EnableTranslator: true}).Generate()
+	if err == nil || !strings.Contains(err.Error(), "refusing to overwrite") || !strings.Contains(err.Error(), "-force") {
+		t.Fatalf("got error %v, want refusing to overwrite with a hint to use -force", err)
+	}
+	if got := readFile(t, filepath.Join(outputDir, "validate.go")); got != legacyFiles["validate.go"] {
+		t.Errorf("validate.go was modified without -force")
+	}
+
+	// 使用 -force 升级一次，之后不再需要
+	for _, force := range []bool{true, false} {
+		if err := NewValidateGenerator(p, &Options{EnableTranslator: true, Force: force}).Generate(); err != nil {
+			t.Fatalf("force %v: %v", force, err)
+		}
+	}
+
+	validateCode := readFile(t, filepath.Join(outputDir, "validate.go"))
+	if !hasGeneratedMarker(validateCode) || !strings.Contains(validateCode, "func (r *LoginReq) Validate() error") {
+		t.Errorf("validate.go was not regenerated with LoginReq.Validate:\n%s", validateCode)
+	}
+	if !hasGeneratedMarker(readFile(t, filepath.Join(outputDir, "translator.go"))) {
+		t.Errorf("translator.go was not regenerated")
+	}
+	if got := readFile(t, filepath.Join(outputDir, "translator_custom.go")); got != legacyFiles["translator_custom.go"] {
+		t.Errorf("translator_custom.go was overwritten")
+	}
+}
+
+// TestScanExistingSkipsOutputTargets 手写文件中的声明仍然参与冲突检查，即将被覆盖的输出文件不参与
+func TestScanExistingSkipsOutputTargets(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "validate.go"), "package types\n\nfunc (r *LoginReq) Validate() error { return nil }\n")
+	writeTestFile(t, filepath.Join(dir, "translator.go"), "package types\n\nfunc Translate(err error) error { return err }\n")
+	writeTestFile(t, filepath.Join(dir, "login.go"), "package types\n\nfunc (r *LogoutReq) Validate() error { return nil }\n")
+
+	// translator.go只在启用翻译器时生成，否则其中的声明仍然参与检查
+	for _, enableTranslator := range []bool{false, true} {
+		g := NewValidateGenerator(&plugin.Plugin{Dir: dir}, &Options{OutputDir: dir, Package: "types", EnableTranslator: enableTranslator})
+		decls, err := g.scanExisting(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var methods []string
+		for recv, names := range decls.methods {
+			if _, ok := names["Validate"]; ok {
+				methods = append(methods, recv)
+			}
+		}
+		if want := []string{"LogoutReq"}; !reflect.DeepEqual(methods, want) {
+			t.Errorf("translator %v: Validate methods on %v, want %v", enableTranslator, methods, want)
+		}
+		if _, ok := decls.idents["Translate"]; ok == enableTranslator {
+			t.Errorf("translator %v: Translate recorded = %v", enableTranslator, ok)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// generatedMarker 生成文件头部的标记，只有带有该标记的文件才会被覆盖
+const generatedMarker = "// Code generated by goctl-validate"
+
+// outputFile 在内存中渲染好的生成文件
+type outputFile struct {
+	Path        string
+	Content     string
+	Description string // 用于日志的文件描述，例如 translator code
+	Protected   bool   // 用户可编辑的文件，只在不存在时创建，不会被覆盖或检查
+	Generated   bool   // 带有生成标记的文件，已存在的同名文件没有标记时拒绝覆盖
+}
+
+// skipped 受保护的文件已经存在时不再处理
+func (f outputFile) skipped() bool {
+	return f.Protected && fileExists(f.Path)
+}
+
+// writeFiles 将生成的文件写入磁盘，输出目录不存在时自动创建。
+// 写入前先检查所有文件，全部写入临时文件后再逐个重命名，失败时不会留下写了一半的包
+func writeFiles(outputDir string, files []outputFile, force bool) error {
+	var pending []outputFile
+	for _, file := range files {
+		if file.skipped() {
+			logf("%s already exists, skipped: %s", file.Description, file.Path)
+			continue
+		}
+		if file.Generated && !force {
+			if err := checkOverwrite(file.Path); err != nil {
+				return err
+			}
+		}
+		pending = append(pending, file)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	temps := make([]string, 0, len(pending))
+	cleanup := func() {
+		for _, temp := range temps {
+			os.Remove(temp)
+		}
+	}
+	for _, file := range pending {
+		temp, err := writeTempFile(file.Path, file.Content)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		temps = append(temps, temp)
+	}
+
+	for i, file := range pending {
+		if err := os.Rename(temps[i], file.Path); err != nil {
+			cleanup()
+			return fmt.Errorf("failed to write %s: %v", file.Path, err)
+		}
+		logf("generated %s in %s", file.Description, file.Path)
+	}
+	return nil
+}
+
+// checkOverwrite 检查已存在的文件是否由goctl-validate生成，避免覆盖手写的文件
+func checkOverwrite(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	if !hasGeneratedMarker(string(content)) {
+		return fmt.Errorf("refusing to overwrite %s: it was not generated by goctl-validate (missing %q header), use -force to overwrite it", path, generatedMarker)
+	}
+	return nil
+}
+
+// hasGeneratedMarker package声明之前是否带有生成标记
+func hasGeneratedMarker(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, generatedMarker) {
+			return true
+		}
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+	}
+	return false
+}
+
+// writeTempFile 将内容写入目标文件同目录下的临时文件，返回临时文件路径
+func writeTempFile(path, content string) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+
+	_, err = f.WriteString(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return f.Name(), nil
+}
+
+// checkFiles 对比生成的内容与磁盘上的文件，存在差异时输出统一diff并返回错误，用于CI检查
+func checkFiles(files []outputFile, staleFiles []staleFile) error {
+	stale := 0
+	for _, file := range staleFiles {
+		if !file.Removable {
+			continue
+		}
+		existing, err := os.ReadFile(file.Path)
+		if err != nil {
+			continue
+		}
+		stale++
+		fmt.Print(unifiedDiff(file.Path, "/dev/null", string(existing), ""))
+	}
+
+	for _, file := range files {
+		if file.skipped() {
+			continue
+		}
+
+		oldName := file.Path
+		existing, err := os.ReadFile(file.Path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read %s: %v", file.Path, err)
+			}
+			oldName = "/dev/null"
+		}
+		if string(existing) == file.Content {
+			continue
+		}
+
+		stale++
+		fmt.Print(unifiedDiff(oldName, file.Path+" (generated)", string(existing), file.Content))
+	}
+
+	if stale > 0 {
+		return fmt.Errorf("%d generated files are out of date, re-run goctl-validate", stale)
+	}
+	logf("generated files are up to date")
+	return nil
+}
+
+// printFiles 将生成的代码打印到标准输出，多个文件时在每个文件前输出文件路径
+func printFiles(files []outputFile) error {
+	var printed []outputFile
+	for _, file := range files {
+		if !file.skipped() && filepath.Base(file.Path) != manifestFile {
+			printed = append(printed, file)
+		}
+	}
+
+	for _, file := range printed {
+		if len(printed) > 1 {
+			fmt.Printf("// ---- %s ----\n", file.Path)
+		}
+		fmt.Print(file.Content)
+	}
+	return nil
+}
+
+// generatedHeader 生成文件的头部注释，遵循Go生成代码的约定（https://go.dev/s/generatedcode），
+// 代码检查和代码审查工具据此识别生成的文件
+func generatedHeader(sources []string) string {
+	return fmt.Sprintf("// Code generated by goctl-validate %s. DO NOT EDIT.\n// source: %s\n\n", Version, strings.Join(sources, ", "))
+}
+
+// formatGoSource 使用gofmt格式化生成的代码，自定义模板生成了无效代码时报错
+func formatGoSource(path, content string) (string, error) {
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return "", fmt.Errorf("generated %s is not valid Go code: %v", path, err)
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readFile 读取文件内容，文件不存在时返回空字符串
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestCheckOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string // 为空表示文件不存在
+		error   string
+	}{
+		{"missing.go", "", ""},
+		{"generated.go", generatedMarker + ". DO NOT EDIT.\n\npackage types\n", ""},
+		{"comment.go", "// Copyright\n" + generatedMarker + ". DO NOT EDIT.\n\npackage types\n", ""},
+		{"handwritten.go", "package types\n\nfunc Validate() {}\n", "refusing to overwrite"},
+		{"late.go", "package types\n\n" + generatedMarker + "\n", "refusing to overwrite"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if tt.content != "" {
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := checkOverwrite(path)
+		if tt.error == "" && err != nil || tt.error != "" && (err == nil || !strings.Contains(err.Error(), tt.error)) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.error)
+		}
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "internal", "types")
+	validatePath := filepath.Join(dir, "validate.go")
+	customPath := filepath.Join(dir, "custom.go")
+	files := []outputFile{
+		{Path: validatePath, Content: generatedMarker + "\n\npackage types\n", Description: "validation code", Generated: true},
+		{Path: customPath, Content: "package types\n", Description: "custom code", Protected: true},
+	}
+
+	// 输出目录不存在时自动创建
+	if err := writeFiles(dir, files, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if got := readFile(t, file.Path); got != file.Content {
+			t.Errorf("%s: got %q, want %q", file.Path, got, file.Content)
+		}
+	}
+
+	// 受保护的文件已存在时保持不变
+	if err := os.WriteFile(customPath, []byte("package types\n\n// edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files[0].Content += "\n// regenerated\n"
+	if err := writeFiles(dir, files, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, validatePath); got != files[0].Content {
+		t.Errorf("validate.go was not regenerated: %q", got)
+	}
+	if got := readFile(t, customPath); !strings.Contains(got, "// edited") {
+		t.Errorf("custom.go was overwritten: %q", got)
+	}
+
+	// 手写文件拒绝覆盖，其他文件也不会写入，-force 时覆盖
+	handwritten := "package types\n\nfunc (r *Req) Validate() error { return nil }\n"
+	if err := os.WriteFile(validatePath, []byte(handwritten), 0644); err != nil {
+		t.Fatal(err)
+	}
+	translatorPath := filepath.Join(dir, "translator.go")
+	files = append(files, outputFile{Path: translatorPath, Content: generatedMarker + "\n\npackage types\n", Description: "translator code", Generated: true})
+	if err := writeFiles(dir, files, false); err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("got error %v, want refusing to overwrite", err)
+	}
+	if got := readFile(t, validatePath); got != handwritten {
+		t.Errorf("handwritten validate.go was modified: %q", got)
+	}
+	if fileExists(translatorPath) {
+		t.Errorf("translator.go was written although validate.go was refused")
+	}
+
+	if err := writeFiles(dir, files, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, validatePath); got != files[0].Content {
+		t.Errorf("validate.go was not overwritten with -force: %q", got)
+	}
+
+	// 不留下临时文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+	}
+}
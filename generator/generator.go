@@ -2,18 +2,22 @@ package generator
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
+	"sort"
 
 	"github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
+// Version 插件版本，写入生成文件的头部
+const Version = "v2.0.0"
+
 // ValidateGenerator 简化的验证代码生成器
 type ValidateGenerator struct {
-	plugin  *plugin.Plugin
-	options *Options
+	plugin   *plugin.Plugin
+	options  *Options
+	warnings int // 已输出的警告数量，严格模式下存在警告时生成失败
 }
 
 // NewValidateGenerator 创建简化的验证代码生成器
@@ -23,6 +27,18 @@ func NewValidateGenerator(p *plugin.Plugin, opts *Options) *ValidateGenerator {
 			EnableTranslator: false,
 		}
 	}
+	if opts.Receiver == "" {
+		opts.Receiver = "r"
+	}
+	if opts.Method == "" {
+		opts.Method = "Validate"
+	}
+	if opts.ValidatorVar == "" {
+		opts.ValidatorVar = "validate"
+	}
+	if opts.TranslatorVar == "" {
+		opts.TranslatorVar = "translator"
+	}
 	return &ValidateGenerator{
 		plugin:  p,
 		options: opts,
@@ -31,260 +47,273 @@ func NewValidateGenerator(p *plugin.Plugin, opts *Options) *ValidateGenerator {
 
 // Generate 生成验证代码
 func (g *ValidateGenerator) Generate() error {
-	// 解析API文件获取带有validate标签的结构体
-	validateStructs, err := g.parseAPIFileForValidateTags()
+	// 打印生成内容时日志输出到标准错误
+	if g.options.Stdout {
+		logOutput = os.Stderr
+	}
+
+	if err := g.options.checkNames(); err != nil {
+		return err
+	}
+
+	// 解析API文件获取所有结构体
+	allStructs, err := g.parseAPIFileForValidateTags()
 	if err != nil {
 		return fmt.Errorf("failed to parse API file: %v", err)
 	}
 
-	if len(validateStructs) == 0 {
-		fmt.Println("goctl-validate: no structures with validate tags found")
-		return nil
+	// 解析输出目录中的手写代码，获取注册的自定义规则和ValidateStruct方法，之后用于检查冲突
+	outputDir := g.outputDir()
+	existing, err := g.scanExisting(outputDir)
+	if err != nil {
+		return err
 	}
 
-	// 查找types目录
-	typesDir := filepath.Join(g.plugin.Dir, "internal", "types")
-	if !dirExists(typesDir) {
-		return fmt.Errorf("types directory not found: %s", typesDir)
+	// 根据类型引用关系挑选需要生成Validate方法的结构体
+	graph := newTypeGraph(allStructs, existing.hooks)
+	for _, warning := range graph.checkTraversal() {
+		g.warnf("%s", warning)
+	}
+	validateStructs := graph.selectValidateStructs()
+
+	// 按照validator的规则语法检查validate标签，并检查跨字段引用
+	issues := append(lintStructs(allStructs, existing.customRules()), graph.checkCrossFieldRefs()...)
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Warning {
+			g.warnf("%s", issue)
+			continue
+		}
+		logf("error - %s", issue)
+		errorCount++
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("found %d invalid validate tags", errorCount)
 	}
 
-	// 生成验证文件
-	validateFile := filepath.Join(typesDir, "validate.go")
-	if err := g.generateValidateFile(validateFile, validateStructs); err != nil {
-		return fmt.Errorf("failed to generate validate file: %v", err)
+	// 读取上次生成时的清单，用于合并多个入口以及清理不再生成的文件
+	previous, err := loadManifest(outputDir)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("goctl-validate: generated validation code for %d structures in %s\n",
-		len(validateStructs), validateFile)
+	// 合并模式下validate.go包含所有入口API文件的类型
+	m := &manifest{}
+	if g.options.Merge {
+		m.Entries = previous.Entries
+		m.prune(g.plugin.Dir)
 
-	// 如果启用翻译器，生成翻译器文件
-	if g.options.EnableTranslator {
-		translatorFile := filepath.Join(typesDir, "translator.go")
-		if err := g.generateTranslatorFile(translatorFile); err != nil {
-			return fmt.Errorf("failed to generate translator file: %v", err)
+		current := entryKey(g.plugin.Dir, g.plugin.ApiFilePath)
+		m.Entries[current] = validateStructs
+		if len(validateStructs) == 0 {
+			delete(m.Entries, current)
 		}
-
-		fmt.Printf("goctl-validate: generated translator code in %s\n", translatorFile)
-
-		// 生成自定义翻译模板文件（如果不存在）
-		customTranslatorFile := filepath.Join(typesDir, "translator_custom.go")
-		if !fileExists(customTranslatorFile) {
-			if err := g.generateCustomTranslatorTemplate(customTranslatorFile); err != nil {
-				return fmt.Errorf("failed to generate custom translator template: %v", err)
-			}
-			fmt.Printf("goctl-validate: generated custom translator template in %s\n", customTranslatorFile)
-		} else {
-			fmt.Printf("goctl-validate: custom translator file already exists, skipped: %s\n", customTranslatorFile)
+		var conflicts []string
+		validateStructs, conflicts = m.merged(current)
+		for _, conflict := range conflicts {
+			g.warnf("%s", conflict)
 		}
+		logf("merged %d structures from %d entry API files", len(validateStructs), len(m.Entries))
 	}
 
-	return nil
-}
+	// 按名称排序，保证重复运行时输出完全一致
+	sort.SliceStable(validateStructs, func(i, j int) bool {
+		return validateStructs[i].Name < validateStructs[j].Name
+	})
 
-// generateValidateFile 生成验证文件
-func (g *ValidateGenerator) generateValidateFile(filename string, validateStructs []ValidateStruct) error {
-	// 准备模板数据
-	data := struct {
-		Package          string
-		EnableTranslator bool
-		Structs          []ValidateStruct
-	}{
-		Package:          "types",
-		EnableTranslator: g.options.EnableTranslator,
-		Structs:          validateStructs,
-	}
-
-	// 生成代码
-	content, err := g.renderTemplate(data)
+	// 手写了Validate方法的类型也可能被其他类型引用，ValidateStruct方法在检查之前收集
+	hooks := graph.structHooks(validateStructs)
+
+	// 已经手写了同名方法的类型不再生成，标识符冲突时报错
+	validateStructs, handwritten, err := g.checkExisting(existing, validateStructs)
 	if err != nil {
-		return fmt.Errorf("failed to render template: %v", err)
+		return err
 	}
 
-	// 写入文件
-	return os.WriteFile(filename, []byte(content), 0644)
-}
-
-// renderTemplate 渲染验证代码模板
-func (g *ValidateGenerator) renderTemplate(data interface{}) (string, error) {
-	tmpl := `package {{.Package}}
+	// 在内存中渲染所有输出文件，再根据模式写入、对比或打印
+	var files []outputFile
+	if len(validateStructs) == 0 {
+		logf("no structures with validate tags found")
+	} else {
+		// 静态模式下将支持的规则生成为普通的Go代码
+		var static *staticCode
+		if g.options.Static {
+			static = g.buildStaticCode(graph, validateStructs)
+		}
+		files, err = g.renderFiles(outputDir, validateStructs, hooks, m, static)
+		if err != nil {
+			return err
+		}
+	}
+	stale := g.staleFiles(outputDir, previous, files)
 
-import (
-	"github.com/go-playground/validator/v10"
-)
+	// 严格模式下任何警告都会导致生成失败，避免产出不完整的验证代码
+	if g.options.Strict && g.warnings > 0 {
+		return fmt.Errorf("strict mode: %d warnings treated as errors", g.warnings)
+	}
 
-// 共享的validator实例
-var validate = validator.New()
+	switch {
+	case g.options.Check:
+		return checkFiles(files, stale)
+	case g.options.Stdout:
+		return printFiles(files)
+	}
 
-{{- range .Structs}}
-// Validate 验证{{.Name}}结构体
-func (r *{{.Name}}) Validate() error {
-	return validate.Struct(r)
+	if err := writeFiles(outputDir, files, g.options.Force); err != nil {
+		return err
+	}
+	if err := removeStaleFiles(stale); err != nil {
+		return err
+	}
+	g.reportRemovedTypes(previous.Types, append(m.Types, handwritten...))
+	return nil
 }
-{{- end}}
-`
 
-	t, err := template.New("validate").Parse(tmpl)
+// renderFiles 渲染validate.go、translator.go等输出文件的内容，hooks为需要注册ValidateStruct方法的类型
+func (g *ValidateGenerator) renderFiles(outputDir string, validateStructs []ValidateStruct, hooks []string, m *manifest, static *staticCode) ([]outputFile, error) {
+	pkg, err := g.packageName(outputDir)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var buf strings.Builder
-	if err := t.Execute(&buf, data); err != nil {
-		return "", err
+	validateFile, err := g.outputFileName(outputDir, "validate")
+	if err != nil {
+		return nil, err
 	}
-
-	return buf.String(), nil
-}
-
-// generateTranslatorFile 生成翻译器文件
-func (g *ValidateGenerator) generateTranslatorFile(filename string) error {
-	content, err := g.renderTranslatorTemplate()
+	translatorFile, err := g.outputFileName(outputDir, "translator")
 	if err != nil {
-		return fmt.Errorf("failed to render translator template: %v", err)
+		return nil, err
 	}
-
-	return os.WriteFile(filename, []byte(content), 0644)
-}
-
-// renderTranslatorTemplate 渲染翻译器模板
-func (g *ValidateGenerator) renderTranslatorTemplate() (string, error) {
-	tmpl := `package types
-
-import (
-	"errors"
-	"fmt"
-	"github.com/go-playground/validator/v10"
-	"github.com/go-playground/validator/v10/translations/zh"
-	"github.com/go-playground/universal-translator"
-	zhongwen "github.com/go-playground/locales/zh"
-)
-
-// 翻译器实例
-var translator ut.Translator
-
-func init() {
-	// 初始化翻译器
-	zw := zhongwen.New()
-	uni := ut.New(zw, zw)
-	trans, _ := uni.GetTranslator("zh")
-	translator = trans
-
-	// 注册官方默认翻译
-	zh.RegisterDefaultTranslations(validate, translator)
-
-	// 注册自定义翻译（如果存在）
-	registerCustomTranslations()
-}
-
-// registerCustomTranslations 注册自定义翻译规则
-// 此方法为预留方法，用于注册自定义的验证规则翻译
-// 自定义翻译应该在 translator_custom.go 文件中实现
-func registerCustomTranslations() {
-	// 检查是否存在自定义翻译注册函数
-	if customRegister := getCustomTranslationRegister(); customRegister != nil {
-		customRegister(validate, translator)
+	customTranslatorFile, err := g.outputFileName(outputDir, "translator_custom")
+	if err != nil {
+		return nil, err
+	}
+	customValidateFile, err := g.outputFileName(outputDir, "validate_custom")
+	if err != nil {
+		return nil, err
 	}
-}
 
-// getCustomTranslationRegister 获取自定义翻译注册函数
-// 这是一个弱引用，如果 translator_custom.go 文件存在，则会被重写
-var getCustomTranslationRegister = func() func(*validator.Validate, ut.Translator) {
-	return nil
-}
+	// 生成文件头部注明插件版本和来源API文件，合并模式下列出所有入口
+	sources := []string{entryKey(g.plugin.Dir, g.plugin.ApiFilePath)}
+	if g.options.Merge {
+		sources = m.entryKeys()
+	}
+	header := generatedHeader(sources)
+
+	// 所有模板共用同一份数据
+	data := TemplateData{
+		Package:            pkg,
+		EnableTranslator:   g.options.EnableTranslator,
+		Structs:            validateStructs,
+		StructHooks:        hooks,
+		CustomFile:         filepath.Base(customTranslatorFile),
+		CustomValidateFile: filepath.Base(customValidateFile),
+		Receiver:           g.options.Receiver,
+		Method:             g.options.Method,
+		CtxMethod:          g.options.ctxMethod(),
+		PartialMethod:      g.options.Method + "Partial",
+		ExceptMethod:       g.options.Method + "Except",
+		ValidatorVar:       g.options.ValidatorVar,
+		TranslatorVar:      g.options.TranslatorVar,
+	}
+	if static != nil {
+		data.Static = true
+		data.Checks = static.Checks
+		data.Samples = static.Samples
+		data.Imports = static.Imports
+		data.Regexps = static.Regexps
+	}
 
-// Translate 翻译验证错误信息
-// 使用方法:
-//   if err := req.Validate(); err != nil {
-//       return Translate(err)
-//   }
-func Translate(err error) error {
-	var validationErrors validator.ValidationErrors
-	if errors.As(err, &validationErrors) {
-		var translatedErrors []string
-		for _, fieldError := range validationErrors {
-			translatedMsg := fieldError.Translate(translator)
-			translatedErrors = append(translatedErrors, translatedMsg)
+	// 验证文件和自定义规则模板
+	content, err := renderTemplate(validateTemplateFile, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate validate file: %v", err)
+	}
+	customContent, err := renderTemplate(customValidateTemplateFile, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate custom validation template: %v", err)
+	}
+	files := []outputFile{{
+		Path:        validateFile,
+		Content:     header + content,
+		Generated:   true,
+		Description: fmt.Sprintf("validation code for %d structures", len(validateStructs)),
+	}, {
+		Path:        customValidateFile,
+		Content:     customContent,
+		Description: "custom validation template",
+		Protected:   true,
+	}}
+
+	// 如果启用翻译器，生成翻译器文件和自定义翻译模板
+	if g.options.EnableTranslator {
+		content, err := renderTemplate(translatorTemplateFile, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate translator file: %v", err)
 		}
-
-		// 返回第一个翻译后的错误信息
-		if len(translatedErrors) > 0 {
-			return fmt.Errorf(translatedErrors[0])
+		customContent, err := renderTemplate(customTranslatorTemplateFile, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate custom translator template: %v", err)
 		}
+		files = append(files, outputFile{
+			Path:        translatorFile,
+			Content:     header + content,
+			Generated:   true,
+			Description: "translator code",
+		}, outputFile{
+			Path:        customTranslatorFile,
+			Content:     customContent,
+			Description: "custom translator template",
+			Protected:   true,
+		})
 	}
 
-	// 如果不是验证错误，返回原始错误
-	return err
-}
-
-// TranslateErrors 翻译所有验证错误信息
-// 返回所有翻译后的错误信息列表
-func TranslateErrors(err error) []string {
-	var translatedErrors []string
-	var validationErrors validator.ValidationErrors
-	if errors.As(err, &validationErrors) {
-		for _, fieldError := range validationErrors {
-			translatedMsg := fieldError.Translate(translator)
-			translatedErrors = append(translatedErrors, translatedMsg)
+	// 静态模式下生成基准测试，对比静态检查与validator的性能
+	if static != nil && len(static.Samples) > 0 {
+		benchFile, err := g.testFileName(outputDir, "validate_bench")
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		// 如果不是验证错误，返回原始错误信息
-		translatedErrors = append(translatedErrors, err.Error())
+		content, err := renderTemplate(benchTemplateFile, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate benchmarks: %v", err)
+		}
+		files = append(files, outputFile{
+			Path:        benchFile,
+			Content:     header + content,
+			Generated:   true,
+			Description: fmt.Sprintf("benchmarks for %d structures", len(static.Samples)),
+		})
 	}
 
-	return translatedErrors
-}
-`
-
-	t, err := template.New("translator").Parse(tmpl)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse translator template: %v", err)
+	// 使用gofmt格式化，保证import顺序和输出稳定
+	for i := range files {
+		formatted, err := formatGoSource(files[i].Path, files[i].Content)
+		if err != nil {
+			return nil, err
+		}
+		files[i].Content = formatted
 	}
 
-	var buf strings.Builder
-	if err := t.Execute(&buf, nil); err != nil {
-		return "", fmt.Errorf("failed to execute translator template: %v", err)
+	// 清单记录本次生成的文件和类型，下次生成时据此清理不再生成的文件
+	m.Files, m.Types = nil, nil
+	for _, file := range files {
+		m.Files = append(m.Files, filepath.Base(file.Path))
 	}
-
-	return buf.String(), nil
-}
-
-// generateCustomTranslatorTemplate 生成自定义翻译器模板文件
-func (g *ValidateGenerator) generateCustomTranslatorTemplate(filename string) error {
-	content := g.renderCustomTranslatorTemplate()
-	return os.WriteFile(filename, []byte(content), 0644)
-}
-
-// renderCustomTranslatorTemplate 渲染自定义翻译器模板
-func (g *ValidateGenerator) renderCustomTranslatorTemplate() string {
-	return `package types
-
-import (
-	"github.com/go-playground/validator/v10"
-	"github.com/go-playground/universal-translator"
-)
-
-// 重写默认的自定义翻译注册函数
-func init() {
-	getCustomTranslationRegister = func() func(*validator.Validate, ut.Translator) {
-		return registerCustomTranslationsImpl
+	for _, validateStruct := range validateStructs {
+		m.Types = append(m.Types, validateStruct.Name)
 	}
-}
+	content, err = m.encode()
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, outputFile{
+		Path:        filepath.Join(outputDir, manifestFile),
+		Content:     content,
+		Description: "manifest",
+	})
 
-// registerCustomTranslationsImpl 注册自定义翻译规则的实现
-// 在这里添加您的自定义验证规则翻译
-// 此文件不会被 goctl-validate 重新生成覆盖
-func registerCustomTranslationsImpl(validate *validator.Validate, translator ut.Translator) {
-	// 示例：注册自定义验证规则翻译
-	// validate.RegisterTranslation("custom_rule", translator, func(ut ut.Translator) error {
-	//     return ut.Add("custom_rule", "{0}不符合自定义规则", true)
-	// }, func(ut ut.Translator, fe validator.FieldError) string {
-	//     t, _ := ut.T("custom_rule", fe.Field())
-	//     return t
-	// })
-
-	// 您可以在这里添加更多自定义翻译规则...
-}
-`
+	return files, nil
 }
 
 // fileExists 检查文件是否存在
@@ -293,7 +322,36 @@ func fileExists(filename string) bool {
 	return !os.IsNotExist(err)
 }
 
-// parseAPIFileForValidateTags 解析API文件获取validate标签
+// parseAPIFileForValidateTags 从goctl解析得到的ApiSpec中获取validate标签，
+// import的文件不存在、循环import、重复的类型等错误已经由goctl在调用插件之前报告
 func (g *ValidateGenerator) parseAPIFileForValidateTags() ([]ValidateStruct, error) {
-	return parseAPIFileForValidateStructs(g.plugin.ApiFilePath)
+	if g.plugin.Api == nil {
+		return nil, fmt.Errorf("api spec not provided")
+	}
+	structs, err := parseSpecForValidateStructs(g.plugin.Api)
+	if err != nil {
+		return nil, err
+	}
+
+	// ApiSpec不包含位置信息，借助行扫描器定位，用于错误提示
+	locateSpecPositions(g.plugin.ApiFilePath, structs)
+	return structs, nil
+}
+
+// logOutput 日志的输出位置，-stdout 模式下输出到标准错误，避免与生成的代码混在一起
+var logOutput io.Writer = os.Stdout
+
+// logf 输出带有 goctl-validate 前缀的日志
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(logOutput, "goctl-validate: "+format+"\n", args...)
+}
+
+// warnf 输出警告，严格模式下以错误级别输出，并在生成前统一失败
+func (g *ValidateGenerator) warnf(format string, args ...interface{}) {
+	level := "warning"
+	if g.options.Strict {
+		level = "error"
+	}
+	logf(level+" - "+format, args...)
+	g.warnings++
 }
@@ -0,0 +1,172 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typeGraph API结构体之间的引用关系图，覆盖主API文件及其import的所有文件
+type typeGraph struct {
+	structs map[string]*ValidateStruct
+	order   []string
+	// needs 记录结构体是否需要生成Validate方法，避免循环引用时重复计算
+	needs map[string]bool
+	// hooks 手写了ValidateStruct方法的结构体，即使没有validate标签也需要验证
+	hooks map[string]string
+}
+
+// typeRef 字段对其他结构体的引用
+type typeRef struct {
+	Target string
+	// Depth 到达被引用结构体需要经过的slice/map层数，每一层都需要一个dive
+	Depth int
+}
+
+// newTypeGraph 根据解析出的所有结构体构建引用关系图
+func newTypeGraph(structs []ValidateStruct, hooks map[string]string) *typeGraph {
+	g := &typeGraph{
+		structs: make(map[string]*ValidateStruct, len(structs)),
+		needs:   make(map[string]bool),
+		hooks:   hooks,
+	}
+
+	for i := range structs {
+		name := structs[i].Name
+		if _, ok := g.structs[name]; !ok {
+			g.order = append(g.order, name)
+		}
+		g.structs[name] = &structs[i]
+	}
+
+	return g
+}
+
+// resolve 按名称查找API中的结构体，不是API中的结构体时返回nil
+func (g *typeGraph) resolve(name string) *ValidateStruct {
+	return g.structs[name]
+}
+
+// fieldRef 解析字段类型引用的结构体，例如 []*OrderItem 引用OrderItem，Depth为1
+func (g *typeGraph) fieldRef(field ValidateField) *typeRef {
+	depth := 0
+	for t := field.Type.Deref(); t != nil; t = t.Elem.Deref() {
+		switch t.Kind {
+		case KindSlice, KindArray, KindMap:
+			depth++
+		case KindNamed:
+			if g.resolve(t.Name) == nil {
+				return nil
+			}
+			return &typeRef{Target: t.Name, Depth: depth}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// walkFields 遍历字段（包括内联匿名结构体的字段），namespace形如 Outer.Inner.Field
+func walkFields(fields []ValidateField, namespace string, fn func(namespace string, field ValidateField)) {
+	for _, field := range fields {
+		fieldNamespace := namespace + "." + field.Name
+		fn(fieldNamespace, field)
+		if len(field.Fields) > 0 && field.ValidateRule != "-" {
+			walkFields(field.Fields, fieldNamespace, fn)
+		}
+	}
+}
+
+// hasRules 结构体自身（包括内联匿名结构体）是否有带validate标签的字段
+func hasRules(validateStruct *ValidateStruct) bool {
+	found := false
+	walkFields(validateStruct.Fields, validateStruct.Name, func(_ string, field ValidateField) {
+		if field.ValidateRule != "" && field.ValidateRule != "-" {
+			found = true
+		}
+	})
+	return found
+}
+
+// needsValidate 判断结构体是否需要生成Validate方法：
+// 自身有validate标签或ValidateStruct方法，或者能够通过字段、内嵌字段引用到这样的结构体
+func (g *typeGraph) needsValidate(name string) bool {
+	validateStruct := g.resolve(name)
+	if validateStruct == nil {
+		return false
+	}
+	if needs, ok := g.needs[validateStruct.Name]; ok {
+		return needs
+	}
+
+	// 先标记为false，处理循环引用
+	g.needs[validateStruct.Name] = false
+	_, hook := g.hooks[validateStruct.Name]
+	needs := hook || hasRules(validateStruct)
+	walkFields(validateStruct.Fields, validateStruct.Name, func(_ string, field ValidateField) {
+		if needs || field.ValidateRule == "-" {
+			return
+		}
+		if ref := g.fieldRef(field); ref != nil {
+			needs = g.needsValidate(ref.Target)
+		}
+	})
+	g.needs[validateStruct.Name] = needs
+	return needs
+}
+
+// checkTraversal 检查引用了带验证规则的结构体，但validate标签无法遍历到的字段
+// validator会自动深入结构体、结构体指针和内嵌字段，但slice和map的元素需要dive才会被验证
+func (g *typeGraph) checkTraversal() []string {
+	var warnings []string
+	for _, name := range g.order {
+		validateStruct := g.structs[name]
+		walkFields(validateStruct.Fields, name, func(namespace string, field ValidateField) {
+			ref := g.fieldRef(field)
+			if ref == nil || !g.needsValidate(ref.Target) {
+				return
+			}
+
+			if field.ValidateRule == "-" {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s (%s) is skipped by validate:\"-\", rules of %s will not be checked",
+					namespace, field.Type, ref.Target))
+				return
+			}
+
+			dives := 0
+			for _, rule := range strings.Split(field.ValidateRule, ",") {
+				if strings.TrimSpace(rule) == "dive" {
+					dives++
+				}
+			}
+			if dives < ref.Depth {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s (%s) references %s which has validate rules, but the tag needs %d `dive` to reach it, e.g. validate:\"%s\"",
+					namespace, field.Type, ref.Target, ref.Depth, strings.TrimSuffix(strings.Repeat("dive,", ref.Depth), ",")))
+			}
+		})
+	}
+	return warnings
+}
+
+// selectValidateStructs 挑选需要生成Validate方法的结构体
+func (g *typeGraph) selectValidateStructs() []ValidateStruct {
+	var result []ValidateStruct
+	for _, name := range g.order {
+		validateStruct := g.structs[name]
+		if !g.needsValidate(name) {
+			continue
+		}
+
+		result = append(result, *validateStruct)
+		if hasRules(validateStruct) {
+			logf("found struct with validate tags: %s (%d fields)",
+				name, len(validateStruct.Fields))
+		} else if pos, ok := g.hooks[name]; ok {
+			logf("found struct with %s method: %s (%s)", structHookMethod, name, pos)
+		} else {
+			logf("found struct embedding or referencing validated types: %s", name)
+		}
+	}
+	return result
+}
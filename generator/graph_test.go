@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newField 创建测试用的字段，typ为Go类型表达式
+func newField(name, typ, rule string) ValidateField {
+	fieldType, err := parseTypeExpr(typ)
+	if err != nil {
+		panic(err)
+	}
+	return ValidateField{Name: name, Type: fieldType, ValidateRule: rule}
+}
+
+// graphStructs 测试用的结构体：Address、OrderItem自身带有规则，User、Order通过字段引用到它们，
+// Plain没有规则，A、B互相引用但都没有规则，Skipped通过 validate:"-" 跳过了引用的结构体，
+// Base内嵌了Address，Inline的内联匿名结构体中引用了OrderItem
+func graphStructs() []ValidateStruct {
+	return []ValidateStruct{
+		{Name: "Address", Fields: []ValidateField{newField("City", "string", "required")}},
+		{Name: "User", Fields: []ValidateField{newField("Name", "string", ""), newField("Addr", "*Address", "")}},
+		{Name: "Order", Fields: []ValidateField{
+			newField("Items", "[]OrderItem", "required"),
+			newField("Groups", "map[string][]*OrderItem", "dive"),
+			newField("Extra", "[]*OrderItem", "dive"),
+		}},
+		{Name: "OrderItem", Fields: []ValidateField{newField("Sku", "string", "required")}},
+		{Name: "Plain", Fields: []ValidateField{newField("Name", "string", "")}},
+		{Name: "A", Fields: []ValidateField{newField("B", "*B", "")}},
+		{Name: "B", Fields: []ValidateField{newField("A", "*A", "")}},
+		{Name: "Skipped", Fields: []ValidateField{newField("Addr", "Address", "-")}},
+		{Name: "Base", Fields: []ValidateField{{Name: "Address", Type: &FieldType{Kind: KindNamed, Name: "Address"}, Embedded: true}}},
+		{Name: "Inline", Fields: []ValidateField{{Name: "Info", Type: &FieldType{Kind: KindStruct}, Fields: []ValidateField{
+			newField("Items", "[]OrderItem", ""),
+		}}}},
+	}
+}
+
+func TestSelectValidateStructs(t *testing.T) {
+	var names []string
+	for _, validateStruct := range newTypeGraph(graphStructs(), nil).selectValidateStructs() {
+		names = append(names, validateStruct.Name)
+	}
+
+	want := []string{"Address", "User", "Order", "OrderItem", "Base", "Inline"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("selected %v, want %v", names, want)
+	}
+}
+
+func TestFieldRef(t *testing.T) {
+	g := newTypeGraph(graphStructs(), nil)
+	tests := []struct {
+		typ    string
+		target string
+		depth  int
+	}{
+		{"Address", "Address", 0},
+		{"*Address", "Address", 0},
+		{"[]*OrderItem", "OrderItem", 1},
+		{"map[string][]OrderItem", "OrderItem", 2},
+		{"string", "", 0},
+		{"[]string", "", 0},
+		{"time.Time", "", 0},
+	}
+
+	for _, tt := range tests {
+		ref := g.fieldRef(newField("F", tt.typ, ""))
+		switch {
+		case tt.target == "" && ref != nil:
+			t.Errorf("%s: got reference to %s, want none", tt.typ, ref.Target)
+		case tt.target != "" && (ref == nil || ref.Target != tt.target || ref.Depth != tt.depth):
+			t.Errorf("%s: got %+v, want %s at depth %d", tt.typ, ref, tt.target, tt.depth)
+		}
+	}
+}
+
+func TestCheckTraversal(t *testing.T) {
+	warnings := newTypeGraph(graphStructs(), nil).checkTraversal()
+
+	want := []string{
+		"Order.Items ([]OrderItem) references OrderItem which has validate rules, but the tag needs 1 `dive`",
+		"Order.Groups (map[string][]*OrderItem) references OrderItem which has validate rules, but the tag needs 2 `dive`",
+		"Skipped.Addr (Address) is skipped by validate:\"-\"",
+		"Inline.Info.Items ([]OrderItem) references OrderItem which has validate rules, but the tag needs 1 `dive`",
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("got %d warnings %q, want %d", len(warnings), warnings, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(warnings[i], want[i]) {
+			t.Errorf("warning %d: got %q, want prefix %q", i, warnings[i], want[i])
+		}
+	}
+}
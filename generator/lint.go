@@ -0,0 +1,392 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lintIssue validate标签检查发现的问题
+type lintIssue struct {
+	File      string
+	Line      int
+	Namespace string
+	Message   string
+	Warning   bool // 语义上可疑但不会导致运行时错误的问题
+}
+
+// String 返回带有API文件位置的问题描述
+func (i lintIssue) String() string {
+	if i.File == "" {
+		return fmt.Sprintf("%s: %s", i.Namespace, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Namespace, i.Message)
+}
+
+// newIssue 创建字段相关的问题
+func newIssue(field ValidateField, namespace, format string, args ...interface{}) lintIssue {
+	return lintIssue{
+		File:      field.File,
+		Line:      field.Line,
+		Namespace: namespace,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// lintStructs 按照validator的规则语法检查所有结构体字段的validate标签，返回发现的问题，
+// custom中注册的自定义规则视为合法
+func lintStructs(structs []ValidateStruct, custom customRules) []lintIssue {
+	var issues []lintIssue
+	for _, validateStruct := range structs {
+		walkFields(validateStruct.Fields, validateStruct.Name, func(namespace string, field ValidateField) {
+			if field.ValidateRule == "" || field.ValidateRule == "-" {
+				return
+			}
+			issues = append(issues, lintRule(namespace, field, custom)...)
+			issues = append(issues, checkZeroValueRules(namespace, field)...)
+		})
+	}
+	return issues
+}
+
+// lintRule 按照dive层级检查规则，map的 dive,keys,...,endkeys 段落针对key类型检查，
+// dive之后的规则针对元素类型检查，每一层的omitempty都必须是该层的第一个规则
+func lintRule(namespace string, field ValidateField, custom customRules) []lintIssue {
+	var issues []lintIssue
+	tokens := strings.Split(field.ValidateRule, ",")
+	current := field.Type
+	segmentStart := 0
+
+	for i := 0; i < len(tokens); i++ {
+		tag := strings.TrimSpace(tokens[i])
+		switch ruleName(tag) {
+		case "":
+			issues = append(issues, newIssue(field, namespace, "empty rule in %q", field.ValidateRule))
+		case "dive":
+			collection := current.Deref()
+			if !collection.IsCollection() {
+				issues = append(issues, newIssue(field, namespace, "'dive' can only be used on slice, array or map, got %s", current))
+				return issues
+			}
+
+			if collection.Kind == KindMap && i+1 < len(tokens) && strings.TrimSpace(tokens[i+1]) == "keys" {
+				end := -1
+				for j := i + 2; j < len(tokens); j++ {
+					if strings.TrimSpace(tokens[j]) == "endkeys" {
+						end = j
+						break
+					}
+				}
+				if end < 0 {
+					issues = append(issues, newIssue(field, namespace, "'keys' is not closed by 'endkeys'"))
+					return issues
+				}
+
+				keyNamespace := namespace + "[key]"
+				for j := i + 2; j < end; j++ {
+					keyTag := strings.TrimSpace(tokens[j])
+					if ruleName(keyTag) == "omitempty" && j != i+2 {
+						issues = append(issues, newIssue(field, keyNamespace, "'omitempty' must be the first rule"))
+						continue
+					}
+					issues = append(issues, checkTag(field, keyNamespace, collection.Key, keyTag, custom)...)
+				}
+				i = end
+			}
+
+			current = collection.Elem
+			namespace += "[]"
+			segmentStart = i + 1
+		case "keys":
+			issues = append(issues, newIssue(field, namespace, "'keys' must directly follow 'dive' on a map"))
+		case "endkeys":
+			issues = append(issues, newIssue(field, namespace, "'endkeys' without matching 'keys'"))
+		case "omitempty":
+			if i != segmentStart {
+				issues = append(issues, newIssue(field, namespace, "'omitempty' must be the first rule, got %q", field.ValidateRule))
+			}
+		default:
+			issues = append(issues, checkTag(field, namespace, current, tag, custom)...)
+		}
+	}
+
+	return issues
+}
+
+// ruleSegment 按dive拆分后的一层规则，以及这一层对应的字段类型
+type ruleSegment struct {
+	Namespace string
+	Type      *FieldType
+	Tags      []string
+}
+
+// diveSegments 按dive将规则拆分为多层，map的 keys...endkeys 部分不属于任何一层
+func diveSegments(namespace string, field ValidateField) []ruleSegment {
+	segment := ruleSegment{Namespace: namespace, Type: field.Type}
+	var segments []ruleSegment
+	inKeys := false
+
+	for _, token := range strings.Split(field.ValidateRule, ",") {
+		tag := strings.TrimSpace(token)
+		switch {
+		case tag == "keys":
+			inKeys = true
+		case tag == "endkeys":
+			inKeys = false
+		case inKeys:
+		case tag == "dive":
+			segments = append(segments, segment)
+			elem := segment.Type.Deref()
+			if !elem.IsCollection() {
+				return segments
+			}
+			segment = ruleSegment{Namespace: segment.Namespace + "[]", Type: elem.Elem}
+		default:
+			segment.Tags = append(segment.Tags, tag)
+		}
+	}
+	return append(segments, segment)
+}
+
+// checkZeroValueRules 检查required与零值合法的规则同时出现的情况：
+// required会拒绝数值0和false，导致oneof中的0、min=0、gte=0以及bool字段的false永远无法通过验证
+func checkZeroValueRules(namespace string, field ValidateField) []lintIssue {
+	var issues []lintIssue
+	for _, segment := range diveSegments(namespace, field) {
+		// 指针类型的required只要求非nil，零值是合法的
+		if segment.Type == nil || segment.Type.Kind == KindPointer {
+			continue
+		}
+
+		class := classOf(segment.Type)
+		if class != classNumber && class != classBool {
+			continue
+		}
+
+		required := false
+		for _, tag := range segment.Tags {
+			if tag == "required" {
+				required = true
+			}
+		}
+		if !required {
+			continue
+		}
+
+		var reason string
+		if class == classBool {
+			reason = "'required' rejects false on bool field"
+		}
+		for _, tag := range segment.Tags {
+			name, param := splitRule(tag)
+			switch {
+			case name == "oneof" && containsZero(strings.Fields(param)):
+				reason = fmt.Sprintf("'required' rejects 0, but '%s' allows it", tag)
+			case (name == "min" || name == "gte") && isZero(param):
+				reason = fmt.Sprintf("'required' rejects 0, but '%s' allows it", tag)
+			}
+		}
+
+		if reason != "" {
+			issue := newIssue(field, segment.Namespace,
+				"%s; use a pointer type (*%s) to require presence, or drop 'required' and rely on go-zero's non-optional json tag (mark it ',optional' if it may be omitted)",
+				reason, segment.Type)
+			issue.Warning = true
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// containsZero 取值列表中是否包含0
+func containsZero(values []string) bool {
+	for _, value := range values {
+		if isZero(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZero 参数是否为数值0
+func isZero(param string) bool {
+	value, err := strconv.ParseFloat(strings.TrimSpace(param), 64)
+	return err == nil && value == 0
+}
+
+// checkTag 检查单个规则（包括 a|b 形式的或规则）：规则是否存在、参数是否合法、是否适用于字段类型
+func checkTag(field ValidateField, namespace string, fieldType *FieldType, tag string, custom customRules) []lintIssue {
+	var issues []lintIssue
+	class := classOf(fieldType)
+
+	for _, alt := range strings.Split(tag, "|") {
+		name, param := splitRule(alt)
+		rule, ok := knownRules[name]
+		if !ok {
+			issues = append(issues, checkCustomRule(field, namespace, name, custom)...)
+			continue
+		}
+
+		if msg := checkParam(name, param, rule, fieldType); msg != "" {
+			issues = append(issues, newIssue(field, namespace, "%s", msg))
+		}
+
+		if class != classAny && rule.Types&class == 0 {
+			issues = append(issues, newIssue(field, namespace, "'%s' cannot be used on %s field (%s)", name, className(class), fieldType))
+		}
+	}
+	return issues
+}
+
+// checkParam 检查规则参数，返回错误描述，参数合法时返回空字符串
+func checkParam(name, param string, rule ruleSpec, fieldType *FieldType) string {
+	switch rule.Param {
+	case paramNone:
+		if param != "" {
+			return fmt.Sprintf("'%s' does not accept a parameter, got %q", name, param)
+		}
+	case paramNumber:
+		if param == "" {
+			return fmt.Sprintf("'%s' requires a numeric parameter", name)
+		}
+		return checkNumberParam(name, param, fieldType)
+	case paramOptional:
+		if param != "" {
+			return checkNumberParam(name, param, fieldType)
+		}
+	case paramValue:
+		if param == "" {
+			return fmt.Sprintf("'%s' requires a parameter", name)
+		}
+		// eq、ne 对字符串按字符串比较，对数值按数值比较，对集合比较元素个数
+		if class := classOf(fieldType); (name == "eq" || name == "ne") && (class == classNumber || class == classCollection) {
+			return checkNumberParam(name, param, fieldType)
+		}
+	case paramList:
+		values := strings.Fields(param)
+		if len(values) == 0 {
+			return fmt.Sprintf("'%s' requires at least one value", name)
+		}
+		if classOf(fieldType) == classNumber {
+			for _, value := range values {
+				if msg := checkOneofValue(name, value, fieldType); msg != "" {
+					return msg
+				}
+			}
+		}
+	case paramField, paramFields:
+		if param == "" {
+			return fmt.Sprintf("'%s' requires a field name", name)
+		}
+	case paramFieldValue:
+		values := strings.Fields(param)
+		if len(values) == 0 || len(values)%2 != 0 {
+			return fmt.Sprintf("'%s' requires field and value pairs, got %q", name, param)
+		}
+	}
+	return ""
+}
+
+// checkNumberParam 按照validator解析参数的方式检查数字参数：
+// 字符串和集合的长度、无符号整数必须是非负整数，有符号整数必须是整数，浮点数可以是小数
+func checkNumberParam(name, param string, fieldType *FieldType) string {
+	t := fieldType.Deref()
+	var err error
+	switch class := classOf(fieldType); {
+	case class == classString || class == classCollection:
+		_, err = strconv.ParseUint(param, 10, 64)
+	case class == classNumber && strings.HasPrefix(t.Name, "float"):
+		_, err = strconv.ParseFloat(param, 64)
+	case class == classNumber && (strings.HasPrefix(t.Name, "uint") || t.Name == "byte"):
+		_, err = strconv.ParseUint(param, 0, 64)
+	case class == classNumber:
+		_, err = strconv.ParseInt(param, 0, 64)
+	default:
+		// 外部包类型（例如time.Duration支持 1h）无法判断
+		return ""
+	}
+
+	if err != nil {
+		return fmt.Sprintf("'%s' parameter %q is not a valid number for %s", name, param, fieldType)
+	}
+	return ""
+}
+
+// checkOneofValue 检查数值字段的取值：validator将整数格式化为十进制后按字符串比较，
+// 0x1、010、+1 这样的写法永远不会匹配；浮点数字段不支持oneof，运行时会panic
+func checkOneofValue(name, value string, fieldType *FieldType) string {
+	t := fieldType.Deref()
+	if strings.HasPrefix(t.Name, "float") {
+		return fmt.Sprintf("'%s' cannot be used on float field (%s)", name, fieldType)
+	}
+	if msg := checkNumberParam(name, value, fieldType); msg != "" {
+		return msg
+	}
+
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(v, 10) == value {
+		return ""
+	}
+	if v, err := strconv.ParseUint(value, 10, 64); err == nil && strconv.FormatUint(v, 10) == value {
+		return ""
+	}
+	return fmt.Sprintf("'%s' value %q never matches on %s, the validator compares integers in plain decimal form", name, value, fieldType)
+}
+
+// splitRule 拆分规则名称和参数，例如 min=3 返回 min 和 3
+func splitRule(tag string) (string, string) {
+	tag = strings.TrimSpace(tag)
+	if idx := strings.Index(tag, "="); idx >= 0 {
+		return strings.TrimSpace(tag[:idx]), tag[idx+1:]
+	}
+	return tag, ""
+}
+
+// ruleName 返回规则名称，例如 min=3 返回 min
+func ruleName(tag string) string {
+	name, _ := splitRule(tag)
+	return name
+}
+
+// suggestRule 为未知规则查找编辑距离最近的内置规则
+func suggestRule(name string) string {
+	var candidates []string
+	for known := range knownRules {
+		candidates = append(candidates, known)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的编辑距离，相邻字符交换算作一次编辑（例如 mni 与 min）
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckParam(t *testing.T) {
+	tests := []struct {
+		typ   string
+		tag   string
+		error string // 期望的错误描述中包含的内容，为空表示参数合法
+	}{
+		{"string", "eq=admin", ""},
+		{"string", "ne=root", ""},
+		{"*string", "eq=admin", ""},
+		{"int", "eq=1", ""},
+		{"int", "eq=abc", "not a valid number"},
+		{"[]string", "eq=2", ""},
+		{"[]string", "eq=x", "not a valid number"},
+		{"string", "min=3", ""},
+		{"string", "min=abc", "not a valid number"},
+		{"string", "min=-1", "not a valid number"},
+		{"string", "min", "requires a numeric parameter"},
+		{"int", "min=-1", ""},
+		{"uint", "min=-1", "not a valid number"},
+		{"float64", "gt=0.5", ""},
+		{"int", "gt=0.5", "not a valid number"},
+		{"string", "required=1", "does not accept a parameter"},
+		{"*string", "omitnil", ""},
+		{"string", "oneof=", "requires at least one value"},
+		{"string", "oneof=a b", ""},
+		{"int", "oneof=1 2 x", "not a valid number"},
+		{"int", "oneof=-1 0 10", ""},
+		{"uint64", "oneof=18446744073709551615", ""},
+		{"int", "oneof=0x1 010", "value \"0x1\" never matches on int"},
+		{"int", "oneof=1 010", "value \"010\" never matches"},
+		{"*int", "oneof=+1", "value \"+1\" never matches on *int"},
+		{"uint", "oneof=1_0", "never matches"},
+		{"int", "oneof=-0", "never matches"},
+		{"float64", "oneof=1 2", "'oneof' cannot be used on float field"},
+		{"string", "eqfield=", "requires a field name"},
+		{"string", "required_if=Type", "requires field and value pairs"},
+		{"string", "required_if=Type a", ""},
+	}
+
+	for _, tt := range tests {
+		field := newField("F", tt.typ, tt.tag)
+		name, param := splitRule(tt.tag)
+		rule, ok := knownRules[name]
+		if !ok {
+			t.Fatalf("%s is not a known rule", name)
+		}
+		msg := checkParam(name, param, rule, field.Type)
+		if tt.error == "" && msg != "" || !strings.Contains(msg, tt.error) {
+			t.Errorf("%s `validate:\"%s\"`: got %q, want %q", tt.typ, tt.tag, msg, tt.error)
+		}
+	}
+}
+
+func TestLintRule(t *testing.T) {
+	tests := []struct {
+		typ      string
+		rule     string
+		messages []string // 期望的问题描述中包含的内容，按顺序对应
+	}{
+		{"string", "required,min=2,max=32", nil},
+		{"string", "omitempty,email", nil},
+		{"*string", "omitnil,min=2", nil},
+		{"string", "eq=admin", nil},
+		{"string", "requred,mni=3", []string{"unknown rule 'requred', did you mean 'required'?", "unknown rule 'mni', did you mean 'min'?"}},
+		{"string", "required,phone", []string{"unknown rule 'phone' is not registered, register it in validate_custom.go"}},
+		{"string", "required,mobile", nil},
+		{"string", "mobile|email", nil},
+		{"string", "required|email", nil},
+		{"string", "min=2,omitempty", []string{"'omitempty' must be the first rule"}},
+		{"string", "required,,min=2", []string{"empty rule"}},
+		{"int", "email", []string{"'email' cannot be used on"}},
+		{"string", "dive,required", []string{"'dive' can only be used on slice, array or map"}},
+		{"[]string", "required,dive,omitempty,min=2", nil},
+		{"[]string", "dive,min=2,omitempty", []string{"'omitempty' must be the first rule"}},
+		{"[]string", "dive,min=abc", []string{"not a valid number"}},
+		{"map[string]int", "dive,keys,min=2,endkeys,gte=0", nil},
+		{"map[string]int", "dive,keys,min=2", []string{"'keys' is not closed by 'endkeys'"}},
+		{"map[string]int", "keys,min=2,endkeys", []string{"'keys' must directly follow 'dive'", "'endkeys' without matching 'keys'"}},
+	}
+
+	custom := customRules{File: "validate_custom.go", Names: map[string]string{"mobile": "validate_custom.go:8:35"}}
+	for _, tt := range tests {
+		issues := lintRule("T.F", newField("F", tt.typ, tt.rule), custom)
+		if len(issues) != len(tt.messages) {
+			t.Errorf("%s `validate:\"%s\"`: got %d issues %v, want %d", tt.typ, tt.rule, len(issues), issues, len(tt.messages))
+			continue
+		}
+		for i, issue := range issues {
+			if !strings.Contains(issue.Message, tt.messages[i]) {
+				t.Errorf("%s `validate:\"%s\"`: got %q, want %q", tt.typ, tt.rule, issue.Message, tt.messages[i])
+			}
+		}
+	}
+}
+
+func TestLintIssueLocation(t *testing.T) {
+	field := newField("Name", "string", "mni=3")
+	field.File, field.Line = "user.api", 12
+
+	issues := lintStructs([]ValidateStruct{{Name: "UserReq", Fields: []ValidateField{field}}}, customRules{})
+	want := "user.api:12: UserReq.Name: unknown rule 'mni', did you mean 'min'?"
+	if len(issues) != 1 || issues[0].String() != want {
+		t.Errorf("got %v, want %q", issues, want)
+	}
+}
+
+func TestCheckZeroValueRules(t *testing.T) {
+	tests := []struct {
+		typ     string
+		rule    string
+		message string // 期望的警告中包含的内容，为空表示没有警告
+	}{
+		{"int", "required,oneof=0 1 2", "'required' rejects 0, but 'oneof=0 1 2' allows it"},
+		{"int", "required,min=0", "'required' rejects 0, but 'min=0' allows it"},
+		{"float64", "required,gte=0.0", "'required' rejects 0, but 'gte=0.0' allows it"},
+		{"bool", "required", "'required' rejects false on bool field"},
+		{"[]int", "dive,required,oneof=0 1", "'required' rejects 0"},
+		{"int", "required,oneof=1 2", ""},
+		{"int", "required,min=1", ""},
+		{"*int", "required,min=0", ""},
+		{"*bool", "required", ""},
+		{"string", "required,min=0", ""},
+	}
+
+	for _, tt := range tests {
+		issues := checkZeroValueRules("T.F", newField("F", tt.typ, tt.rule))
+		switch {
+		case tt.message == "" && len(issues) != 0:
+			t.Errorf("%s `validate:\"%s\"`: got %v, want no warning", tt.typ, tt.rule, issues)
+		case tt.message != "" && (len(issues) != 1 || !issues[0].Warning || !strings.Contains(issues[0].Message, tt.message)):
+			t.Errorf("%s `validate:\"%s\"`: got %v, want warning %q", tt.typ, tt.rule, issues, tt.message)
+		}
+	}
+}
+
+func TestCheckCustomRule(t *testing.T) {
+	custom := customRules{File: "validate_custom.go", Names: map[string]string{"mobile": "validate_custom.go:8:35"}}
+	tests := []struct {
+		name    string
+		message string // 为空表示没有问题
+		warning bool
+	}{
+		{"mobile", "", false},
+		{"requred", "unknown rule 'requred', did you mean 'required'?", false},
+		{"mobiel", "unknown rule 'mobiel' is not registered", true},
+		{"id_card", "unknown rule 'id_card' is not registered, register it in validate_custom.go", true},
+	}
+
+	for _, tt := range tests {
+		issues := checkCustomRule(newField("F", "string", tt.name), "T.F", tt.name, custom)
+		switch {
+		case tt.message == "" && len(issues) != 0:
+			t.Errorf("%s: got %v, want no issue", tt.name, issues)
+		case tt.message != "" && (len(issues) != 1 || issues[0].Warning != tt.warning || !strings.Contains(issues[0].Message, tt.message)):
+			t.Errorf("%s: got %v, want %q (warning %v)", tt.name, issues, tt.message, tt.warning)
+		}
+	}
+}
+
+// TestLintCustomRuleLevels 内置规则的拼写错误是错误，其余没有注册的规则只是警告
+func TestLintCustomRuleLevels(t *testing.T) {
+	custom := customRules{File: "validate_custom.go", Names: map[string]string{"mobile": "validate_custom.go:8:35"}}
+	field := newField("Phone", "string", "required,mni=2,mobile,id_card")
+	issues := lintStructs([]ValidateStruct{{Name: "UserReq", Fields: []ValidateField{field}}}, custom)
+
+	want := []struct {
+		message string
+		warning bool
+	}{
+		{"unknown rule 'mni', did you mean 'min'?", false},
+		{"unknown rule 'id_card' is not registered", true},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues %v, want %d", len(issues), issues, len(want))
+	}
+	for i, issue := range issues {
+		if !strings.Contains(issue.Message, want[i].message) || issue.Warning != want[i].warning {
+			t.Errorf("issue %d: got %q (warning %v), want %q (warning %v)", i, issue.Message, issue.Warning, want[i].message, want[i].warning)
+		}
+	}
+}
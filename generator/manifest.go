@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// manifestFile 清单文件，保存在输出目录中，应当与生成的代码一起提交
+const manifestFile = ".goctl-validate.json"
+
+// manifest 记录插件在输出目录中生成的文件和类型，用于清理不再生成的文件；
+// 合并模式下还记录每个入口API文件生成了哪些类型，validate.go 包含所有入口的类型
+type manifest struct {
+	Files   []string                    `json:"files,omitempty"`   // 生成的文件，相对于输出目录
+	Types   []string                    `json:"types,omitempty"`   // 生成了Validate方法的类型
+	Entries map[string][]ValidateStruct `json:"entries,omitempty"` // 合并模式下每个入口API文件生成的类型
+}
+
+// loadManifest 读取输出目录中的清单，清单不存在时返回空清单
+func loadManifest(dir string) (*manifest, error) {
+	m := &manifest{Entries: make(map[string][]ValidateStruct)}
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %v", filepath.Join(dir, manifestFile), err)
+	}
+	if m.Entries == nil {
+		m.Entries = make(map[string][]ValidateStruct)
+	}
+	return m, nil
+}
+
+// encode 编码清单内容
+func (m *manifest) encode() (string, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest: %v", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// entryKeys 按字典序返回所有入口，保证合并结果的顺序稳定
+func (m *manifest) entryKeys() []string {
+	keys := make([]string, 0, len(m.Entries))
+	for key := range m.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// prune 删除入口API文件已经不存在的记录，入口路径相对于baseDir
+func (m *manifest) prune(baseDir string) {
+	for _, key := range m.entryKeys() {
+		if !fileExists(filepath.Join(baseDir, filepath.FromSlash(key))) {
+			delete(m.Entries, key)
+			logf("entry %s no longer exists, removed from manifest", key)
+		}
+	}
+}
+
+// merged 合并所有入口生成的类型，同名且内容相同的类型只保留一个；
+// 内容不同时以当前入口的定义为准，并返回冲突描述
+func (m *manifest) merged(current string) ([]ValidateStruct, []string) {
+	var result []ValidateStruct
+	var conflicts []string
+	owners := make(map[string]string)
+	indexes := make(map[string]int)
+
+	for _, key := range m.entryKeys() {
+		for _, validateStruct := range m.Entries[key] {
+			idx, ok := indexes[validateStruct.Name]
+			if !ok {
+				indexes[validateStruct.Name] = len(result)
+				owners[validateStruct.Name] = key
+				result = append(result, validateStruct)
+				continue
+			}
+
+			if sameStruct(result[idx], validateStruct) {
+				continue
+			}
+
+			winner := owners[validateStruct.Name]
+			if key == current {
+				result[idx] = validateStruct
+				winner = key
+			}
+			conflicts = append(conflicts, fmt.Sprintf("type %s differs between %s and %s, using the definition from %s",
+				validateStruct.Name, owners[validateStruct.Name], key, winner))
+			owners[validateStruct.Name] = winner
+		}
+	}
+	return result, conflicts
+}
+
+// entryKey 入口API文件在清单中的名称，使用相对于baseDir的路径
+func entryKey(baseDir, apiFilePath string) string {
+	absBase, err1 := filepath.Abs(baseDir)
+	absFile, err2 := filepath.Abs(apiFilePath)
+	if err1 == nil && err2 == nil {
+		if rel, err := filepath.Rel(absBase, absFile); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(apiFilePath)
+}
+
+// sameStruct 两个同名类型的声明是否相同，只比较字段，不比较声明位置
+func sameStruct(a, b ValidateStruct) bool {
+	return sameFields(a.Fields, b.Fields)
+}
+
+// sameFields 比较字段名、类型、标签以及内联结构体的字段
+func sameFields(a, b []ValidateField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name ||
+			a[i].Type.String() != b[i].Type.String() ||
+			a[i].ValidateRule != b[i].ValidateRule ||
+			a[i].JsonTag != b[i].JsonTag ||
+			a[i].Embedded != b[i].Embedded ||
+			!sameFields(a[i].Fields, b[i].Fields) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// structNames 返回结构体名称列表
+func structNames(structs []ValidateStruct) []string {
+	var names []string
+	for _, validateStruct := range structs {
+		names = append(names, validateStruct.Name)
+	}
+	return names
+}
+
+func TestManifestMerged(t *testing.T) {
+	page := ValidateStruct{Name: "PageReq", Fields: []ValidateField{newField("Page", "int", "min=1")}}
+	adminPage := ValidateStruct{Name: "PageReq", Fields: []ValidateField{newField("Page", "int", "min=0")}}
+	m := &manifest{Entries: map[string][]ValidateStruct{
+		"user.api":  {{Name: "UserReq", Fields: []ValidateField{newField("Name", "string", "required")}}, page},
+		"order.api": {{Name: "OrderReq", Fields: []ValidateField{newField("ID", "int64", "required")}}, page},
+	}}
+
+	// 相同的类型只保留一个，按入口名称排序
+	structs, conflicts := m.merged("user.api")
+	if want := []string{"OrderReq", "PageReq", "UserReq"}; !reflect.DeepEqual(structNames(structs), want) {
+		t.Errorf("merged %v, want %v", structNames(structs), want)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("got conflicts %v, want none", conflicts)
+	}
+
+	// 内容不同时以当前入口的定义为准
+	m.Entries["admin.api"] = []ValidateStruct{adminPage}
+	for _, current := range []string{"admin.api", "user.api"} {
+		structs, conflicts = m.merged(current)
+		if len(conflicts) == 0 || !strings.Contains(conflicts[0], "type PageReq differs between admin.api and") {
+			t.Errorf("current %s: got conflicts %v", current, conflicts)
+		}
+		for _, validateStruct := range structs {
+			if validateStruct.Name != "PageReq" {
+				continue
+			}
+			want := m.Entries[current][len(m.Entries[current])-1]
+			if !sameStruct(validateStruct, want) {
+				t.Errorf("current %s: PageReq uses rule %q, want %q", current,
+					validateStruct.Fields[0].ValidateRule, want.Fields[0].ValidateRule)
+			}
+		}
+	}
+}
+
+func TestManifestPrune(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "user.api"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &manifest{Entries: map[string][]ValidateStruct{
+		"user.api":    {{Name: "UserReq"}},
+		"removed.api": {{Name: "RemovedReq"}},
+	}}
+	m.prune(dir)
+	if want := []string{"user.api"}; !reflect.DeepEqual(m.entryKeys(), want) {
+		t.Errorf("entries after prune %v, want %v", m.entryKeys(), want)
+	}
+}
+
+func TestManifestEncodeLoad(t *testing.T) {
+	dir := t.TempDir()
+	m, err := loadManifest(dir)
+	if err != nil || len(m.Entries) != 0 {
+		t.Fatalf("load missing manifest: %v, %v", m, err)
+	}
+
+	field := newField("Items", "map[string][]*Item", "dive,keys,min=1,endkeys,required")
+	field.JsonTag = "items"
+	field.File, field.Line = "user.api", 3
+	m.Entries[entryKey(dir, filepath.Join(dir, "api", "user.api"))] = []ValidateStruct{{Name: "UserReq", Fields: []ValidateField{field}}}
+	content, err := m.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	structs := loaded.Entries["api/user.api"]
+	if len(structs) != 1 || !sameStruct(structs[0], m.Entries["api/user.api"][0]) {
+		t.Errorf("loaded %+v, want the saved UserReq", loaded.Entries)
+	}
+}
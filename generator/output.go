@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/zeromicro/go-zero/tools/goctl/util/format"
+)
+
+// defaultOutputDir 默认的输出目录，与goctl生成types.go的目录一致
+var defaultOutputDir = filepath.Join("internal", "types")
+
+// outputDir 返回生成代码的目标目录，相对路径基于plugin.Dir
+func (g *ValidateGenerator) outputDir() string {
+	dir := g.options.OutputDir
+	if dir == "" {
+		dir = defaultOutputDir
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(g.plugin.Dir, dir)
+}
+
+// outputFileName 按照goctl的 --style 格式化输出文件名，例如 validate.go、Validate.go、validate_gen.go
+// 没有指定style时保持原有的文件名，避免已有项目中的文件被重命名
+func (g *ValidateGenerator) outputFileName(dir, name string) (string, error) {
+	if g.plugin.Style != "" {
+		formatted, err := format.FileNamingFormat(g.plugin.Style, name)
+		if err != nil {
+			return "", fmt.Errorf("invalid style %q: %v", g.plugin.Style, err)
+		}
+		name = formatted
+	}
+	return filepath.Join(dir, name+".go"), nil
+}
+
+// testFileName 测试文件名，按照style格式化后再加上 _test 后缀，保证文件仍然被识别为测试文件
+func (g *ValidateGenerator) testFileName(dir, name string) (string, error) {
+	path, err := g.outputFileName(dir, name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, ".go") + "_test.go", nil
+}
+
+// packageName 确定生成代码的包名：优先使用指定的包名，其次使用目录中已有Go文件的包名，
+// 目录中没有Go文件时使用目录名
+func (g *ValidateGenerator) packageName(dir string) (string, error) {
+	detected, err := detectPackage(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if g.options.Package != "" {
+		// 同一目录下的Go文件必须属于同一个包
+		if detected != "" && detected != g.options.Package {
+			return "", fmt.Errorf("package %s conflicts with existing package %s in %s", g.options.Package, detected, dir)
+		}
+		return g.options.Package, nil
+	}
+
+	if detected != "" {
+		return detected, nil
+	}
+	return packageFromDir(dir), nil
+}
+
+// detectPackage 读取目录中已有Go文件的package声明，目录中没有Go文件时返回空字符串
+func detectPackage(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read output directory %s: %v", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := goparser.ParseFile(fset, filepath.Join(dir, name), nil, goparser.PackageClauseOnly)
+		if err != nil {
+			// 无法解析的文件不影响包名判断
+			continue
+		}
+		return file.Name.Name, nil
+	}
+	return "", nil
+}
+
+// packageFromDir 根据目录名生成合法的包名，例如 internal/types 返回 types
+func packageFromDir(dir string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(dir)) {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		return "types"
+	}
+	return name
+}
@@ -9,49 +9,58 @@ import (
 	"strings"
 )
 
-// ValidateStruct 包含validate标签的结构体信息
+// ValidateStruct API中定义的结构体信息
 type ValidateStruct struct {
-	Name   string
-	Fields []ValidateField
+	Name   string          `json:"name"`
+	Fields []ValidateField `json:"fields,omitempty"`
+	File   string          `json:"-"` // 结构体所在的API文件
+	Line   int             `json:"-"` // 结构体定义所在的行号
 }
 
-// ValidateField 包含validate标签的字段信息
+// ValidateField 结构体的字段信息，ValidateRule为空表示该字段没有validate标签
 type ValidateField struct {
-	Name         string
-	Type         string
-	ValidateRule string
-	JsonTag      string
+	Name         string          `json:"name"`
+	Type         *FieldType      `json:"type,omitempty"`
+	ValidateRule string          `json:"validate,omitempty"`
+	JsonTag      string          `json:"json,omitempty"`
+	Embedded     bool            `json:"embedded,omitempty"` // 是否为内嵌字段，内嵌字段以类型名作为字段名
+	Fields       []ValidateField `json:"fields,omitempty"`   // 内联匿名结构体的字段
+	File         string          `json:"-"`                  // 字段所在的API文件
+	Line         int             `json:"-"`                  // 字段所在的行号
 }
 
 // Options 插件选项
 type Options struct {
-	EnableTranslator bool // 是否生成translator
+	EnableTranslator bool   // 是否生成translator
+	Strict           bool   // 严格模式，所有警告都当作错误
+	OutputDir        string // 输出目录，相对路径基于plugin.Dir，默认为 internal/types
+	Package          string // 生成代码的包名，默认从输出目录中已有的Go文件检测
+	Merge            bool   // 合并模式，多个入口API文件生成到同一个目录时合并各自的类型
+	Check            bool   // 只检查磁盘上的生成文件是否最新，不写入文件
+	Stdout           bool   // 将生成的内容打印到标准输出，不写入文件
+	Force            bool   // 覆盖没有生成标记的同名文件
+	Receiver         string // 生成方法的接收者名称，默认为 r
+	Method           string // 生成的验证方法名，默认为 Validate
+	ValidatorVar     string // 共享validator实例的变量名，默认为 validate
+	TranslatorVar    string // 翻译器实例的变量名，默认为 translator
+	Static           bool   // 静态模式，将支持的规则生成为普通的Go代码，验证通过时不使用反射
 }
 
-// parseAPIFileForValidateStructs 解析API文件获取带有validate标签的结构体（支持import）
-func parseAPIFileForValidateStructs(apiFilePath string) ([]ValidateStruct, error) {
-	var allValidateStructs []ValidateStruct
-	processedFiles := make(map[string]bool)
-
-	// 解析主API文件和所有import的文件
-	if err := parseAPIFileRecursively(apiFilePath, &allValidateStructs, processedFiles); err != nil {
-		return nil, err
-	}
-
-	fmt.Printf("goctl-validate: found %d structures with validate tags across %d files\n",
-		len(allValidateStructs), len(processedFiles))
-	return allValidateStructs, nil
+// apiScanner API文件行扫描器，ApiSpec不包含位置信息，扫描器只用于定位结构体和字段在API文件中的位置，
+// API文件的错误由goctl的解析器报告，扫描器遇到无法识别的内容时直接跳过
+type apiScanner struct {
+	structs        []ValidateStruct
+	processedFiles map[string]bool
 }
 
 // parseAPIFileRecursively 递归解析API文件及其import的文件
-func parseAPIFileRecursively(apiFilePath string, validateStructs *[]ValidateStruct, processedFiles map[string]bool) error {
-	// 避免重复处理同一个文件
-	if processedFiles[apiFilePath] {
+// 支持 type ( ... ) 块、单行 type Foo { ... } 以及可选的struct关键字
+func (s *apiScanner) parseAPIFileRecursively(apiFilePath string) error {
+	// 避免重复处理同一个文件，同时避免循环import导致无限递归
+	if s.processedFiles[apiFilePath] {
 		return nil
 	}
-	processedFiles[apiFilePath] = true
-
-	fmt.Printf("goctl-validate: parsing API file: %s\n", apiFilePath)
+	s.processedFiles[apiFilePath] = true
 
 	file, err := os.Open(apiFilePath)
 	if err != nil {
@@ -65,10 +74,50 @@ func parseAPIFileRecursively(apiFilePath string, validateStructs *[]ValidateStru
 	var inTypeBlock bool
 	var inImportBlock bool
 	var inStruct bool
-	var braceCount int
+	// inlineStack 正在解析的内联匿名结构体，栈顶为最内层
+	var inlineStack []*ValidateField
+	var lineNum int
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		// 在结构体内部
+		if inStruct && currentStruct != nil {
+			switch {
+			case strings.HasPrefix(line, "}"):
+				// 外层结构体结束
+				if len(inlineStack) == 0 {
+					s.addStruct(currentStruct, apiFilePath)
+					currentStruct = nil
+					inStruct = false
+					continue
+				}
+
+				// 内联结构体结束，标签写在右大括号之后: } `json:"addr"`
+				inline := inlineStack[len(inlineStack)-1]
+				inlineStack = inlineStack[:len(inlineStack)-1]
+				tags := extractTagsFromLine(line)
+				inline.ValidateRule = extractValidateFromTags(tags)
+				inline.JsonTag = extractJsonFromTags(tags)
+				appendField(currentStruct, inlineStack, *inline)
+			case inlineStructRegex.MatchString(line):
+				// 内联结构体开始: Addr { 或 Addr struct {
+				matches := inlineStructRegex.FindStringSubmatch(line)
+				inlineStack = append(inlineStack, &ValidateField{
+					Name: matches[1],
+					Type: &FieldType{Kind: KindStruct},
+					File: apiFilePath,
+					Line: lineNum,
+				})
+			default:
+				// 解析字段或内嵌字段
+				if field := s.parseField(line, apiFilePath, lineNum); field != nil {
+					appendField(currentStruct, inlineStack, *field)
+				}
+			}
+			continue
+		}
 
 		// 检查import块开始
 		if strings.HasPrefix(line, "import (") {
@@ -84,23 +133,17 @@ func parseAPIFileRecursively(apiFilePath string, validateStructs *[]ValidateStru
 
 		// 在import块内部或单行import
 		if inImportBlock {
-			if importPath := parseImportPathFromLine(line, apiFilePath); importPath != "" {
-				// 递归解析import的文件
-				if err := parseAPIFileRecursively(importPath, validateStructs, processedFiles); err != nil {
-					fmt.Printf("goctl-validate: warning - failed to parse imported file %s: %v\n", importPath, err)
-				}
-			}
+			importPath, err := parseImportPathFromLine(line, apiFilePath)
+			s.importFile(importPath, err)
 			continue
-		} else if importPath := parseImportLine(line, apiFilePath); importPath != "" {
+		} else if importPath, err := parseImportLine(line, apiFilePath); importPath != "" || err != nil {
 			// 单行import
-			if err := parseAPIFileRecursively(importPath, validateStructs, processedFiles); err != nil {
-				fmt.Printf("goctl-validate: warning - failed to parse imported file %s: %v\n", importPath, err)
-			}
+			s.importFile(importPath, err)
 			continue
 		}
 
 		// 检查是否进入type块
-		if strings.HasPrefix(line, "type (") {
+		if typeBlockRegex.MatchString(line) {
 			inTypeBlock = true
 			continue
 		}
@@ -111,45 +154,38 @@ func parseAPIFileRecursively(apiFilePath string, validateStructs *[]ValidateStru
 			continue
 		}
 
-		if !inTypeBlock {
+		// type块内的声明，或者单行 type 声明
+		decl := line
+		if strings.HasPrefix(line, "type ") {
+			decl = strings.TrimSpace(strings.TrimPrefix(line, "type "))
+		} else if !inTypeBlock {
 			continue
 		}
 
 		// 检查结构体定义开始
-		if strings.Contains(line, "{") && !inStruct {
-			structName := extractStructName(line)
-			if structName != "" {
-				currentStruct = &ValidateStruct{
-					Name:   structName,
-					Fields: []ValidateField{},
-				}
-				inStruct = true
-				braceCount = strings.Count(line, "{") - strings.Count(line, "}")
-				continue
+		if structName := extractStructName(decl); structName != "" {
+			currentStruct = &ValidateStruct{
+				Name:   structName,
+				Fields: []ValidateField{},
+				File:   apiFilePath,
+				Line:   lineNum,
 			}
-		}
-
-		// 在结构体内部
-		if inStruct && currentStruct != nil {
-			// 更新大括号计数
-			braceCount += strings.Count(line, "{") - strings.Count(line, "}")
+			braceCount := strings.Count(decl, "{") - strings.Count(decl, "}")
 
-			// 检查结构体结束
+			// type Empty {} 这样在同一行结束的结构体
 			if braceCount <= 0 {
-				if len(currentStruct.Fields) > 0 {
-					*validateStructs = append(*validateStructs, *currentStruct)
-					fmt.Printf("goctl-validate: found struct with validate tags: %s (%d fields) in %s\n",
-						currentStruct.Name, len(currentStruct.Fields), apiFilePath)
+				body := decl[strings.Index(decl, "{")+1 : strings.LastIndex(decl, "}")]
+				if field := s.parseField(strings.TrimSpace(body), apiFilePath, lineNum); field != nil {
+					currentStruct.Fields = append(currentStruct.Fields, *field)
 				}
+				s.addStruct(currentStruct, apiFilePath)
 				currentStruct = nil
-				inStruct = false
 				continue
 			}
 
-			// 解析字段
-			if field := parseFieldLine(line); field != nil {
-				currentStruct.Fields = append(currentStruct.Fields, *field)
-			}
+			inStruct = true
+			inlineStack = nil
+			continue
 		}
 	}
 
@@ -160,53 +196,138 @@ func parseAPIFileRecursively(apiFilePath string, validateStructs *[]ValidateStru
 	return nil
 }
 
+// importFile 递归解析import的文件，无法解析的文件中的位置保持为空
+func (s *apiScanner) importFile(importPath string, err error) {
+	if err != nil || importPath == "" {
+		return
+	}
+	s.parseAPIFileRecursively(importPath)
+}
+
+// addStruct 记录解析完成的结构体
+// 没有validate标签的结构体也需要保留，用于构建类型引用关系图
+func (s *apiScanner) addStruct(validateStruct *ValidateStruct, apiFilePath string) {
+	s.structs = append(s.structs, *validateStruct)
+}
+
+// parseField 解析结构体内的字段行或内嵌字段行，并记录字段位置
+func (s *apiScanner) parseField(line, apiFilePath string, lineNum int) *ValidateField {
+	field, err := parseFieldLine(line)
+	if err != nil {
+		return nil
+	}
+
+	if field == nil {
+		field = parseEmbeddedLine(line)
+		if field == nil {
+			return nil
+		}
+	}
+
+	field.File = apiFilePath
+	field.Line = lineNum
+	return field
+}
+
+var (
+	// typeBlockRegex 匹配 type ( 块的开始
+	typeBlockRegex = regexp.MustCompile(`^type\s*\($`)
+	// structNameRegex 匹配结构体定义: StructName { 或 StructName struct {
+	structNameRegex = regexp.MustCompile(`^(\w+)\s*(?:struct\s*)?\{`)
+	// inlineStructRegex 匹配结构体内的内联匿名结构体: Addr { 或 Addr struct {
+	inlineStructRegex = regexp.MustCompile(`^(\w+)\s*(?:struct\s*)?\{$`)
+	// embeddedRegex 匹配内嵌字段: BaseReq 或 *BaseReq
+	embeddedRegex = regexp.MustCompile(`^\*?([\w.]+)\s*(?://.*)?$`)
+	// tagsRegex 匹配行内的标签部分
+	tagsRegex = regexp.MustCompile("`([^`]*)`")
+)
+
 // extractStructName 从行中提取结构体名称
 func extractStructName(line string) string {
-	// 匹配结构体定义: StructName {
-	re := regexp.MustCompile(`(\w+)\s*\{`)
-	matches := re.FindStringSubmatch(line)
+	matches := structNameRegex.FindStringSubmatch(line)
 	if len(matches) > 1 {
 		return matches[1]
 	}
 	return ""
 }
 
-// parseFieldLine 解析字段行
-func parseFieldLine(line string) *ValidateField {
-	// 跳过注释行
+// appendField 将字段添加到当前最内层的结构体中
+func appendField(currentStruct *ValidateStruct, inlineStack []*ValidateField, field ValidateField) {
+	if len(inlineStack) > 0 {
+		inline := inlineStack[len(inlineStack)-1]
+		inline.Fields = append(inline.Fields, field)
+		return
+	}
+	currentStruct.Fields = append(currentStruct.Fields, field)
+}
+
+// parseEmbeddedLine 解析内嵌字段行，内嵌字段以类型名作为字段名
+func parseEmbeddedLine(line string) *ValidateField {
 	if strings.HasPrefix(line, "//") {
 		return nil
 	}
 
-	// 匹配字段定义: FieldName Type `tags`
-	re := regexp.MustCompile(`(\w+)\s+([*\[\]]*\w+)\s*` + "`" + `([^` + "`" + `]*)` + "`")
-	matches := re.FindStringSubmatch(line)
+	matches := embeddedRegex.FindStringSubmatch(line)
+	if len(matches) < 2 {
+		return nil
+	}
 
-	if len(matches) < 4 {
+	fieldType, err := parseTypeExpr(strings.TrimSpace(strings.Split(line, "//")[0]))
+	if err != nil {
 		return nil
 	}
 
-	fieldName := matches[1]
-	fieldType := matches[2]
-	tags := matches[3]
+	name := matches[1]
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
 
-	// 解析标签
-	validateRule := extractValidateFromTags(tags)
-	if validateRule == "" {
-		return nil
+	return &ValidateField{
+		Name:     name,
+		Type:     fieldType,
+		Embedded: true,
+	}
+}
+
+// extractTagsFromLine 提取行内反引号中的标签字符串
+func extractTagsFromLine(line string) string {
+	matches := tagsRegex.FindStringSubmatch(line)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// fieldLineRegex 匹配字段定义: FieldName Type `tags` // comment，标签和注释可省略
+var fieldLineRegex = regexp.MustCompile("^(\\w+)\\s+([^`/]+?)\\s*(?:`([^`]*)`)?\\s*(?://.*)?$")
+
+// parseFieldLine 解析字段行，没有validate标签的字段也会返回，用于分析类型引用
+// 不是字段定义的行返回nil，类型无法解析时返回错误
+func parseFieldLine(line string) (*ValidateField, error) {
+	// 跳过注释行
+	if strings.HasPrefix(line, "//") {
+		return nil, nil
+	}
+
+	matches := fieldLineRegex.FindStringSubmatch(line)
+	if len(matches) < 4 {
+		return nil, nil
 	}
 
-	jsonTag := extractJsonFromTags(tags)
+	fieldName := matches[1]
+	tags := matches[3]
 
-	fmt.Printf("goctl-validate: found field with validate: %s (%s) validate='%s'\n",
-		fieldName, fieldType, validateRule)
+	fieldType, err := parseTypeExpr(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %v", fieldName, err)
+	}
 
 	return &ValidateField{
 		Name:         fieldName,
 		Type:         fieldType,
-		ValidateRule: validateRule,
-		JsonTag:      jsonTag,
-	}
+		ValidateRule: extractValidateFromTags(tags),
+		JsonTag:      extractJsonFromTags(tags),
+	}, nil
 }
 
 // extractValidateFromTags 从标签字符串中提取validate值
@@ -232,29 +353,29 @@ func extractJsonFromTags(tags string) string {
 }
 
 // parseImportLine 解析单行import语句
-func parseImportLine(line, currentFilePath string) string {
+func parseImportLine(line, currentFilePath string) (string, error) {
 	// 匹配 import "path/to/file.api"
 	re := regexp.MustCompile(`import\s+"([^"]+\.api)"`)
 	matches := re.FindStringSubmatch(line)
 	if len(matches) > 1 {
 		return resolveImportPath(matches[1], currentFilePath)
 	}
-	return ""
+	return "", nil
 }
 
 // parseImportPathFromLine 解析import块内的路径行
-func parseImportPathFromLine(line, currentFilePath string) string {
+func parseImportPathFromLine(line, currentFilePath string) (string, error) {
 	// 匹配 "path/to/file.api"
 	re := regexp.MustCompile(`"([^"]+\.api)"`)
 	matches := re.FindStringSubmatch(line)
 	if len(matches) > 1 {
 		return resolveImportPath(matches[1], currentFilePath)
 	}
-	return ""
+	return "", nil
 }
 
 // resolveImportPath 解析并验证import路径
-func resolveImportPath(importPath, currentFilePath string) string {
+func resolveImportPath(importPath, currentFilePath string) (string, error) {
 	// 如果是相对路径，转换为绝对路径
 	if !filepath.IsAbs(importPath) {
 		baseDir := filepath.Dir(currentFilePath)
@@ -262,16 +383,9 @@ func resolveImportPath(importPath, currentFilePath string) string {
 	}
 
 	// 检查文件是否存在
-	if _, err := os.Stat(importPath); err == nil {
-		return importPath
+	if _, err := os.Stat(importPath); err != nil {
+		return "", fmt.Errorf("imported API file not found: %s (imported by %s)", importPath, currentFilePath)
 	}
 
-	fmt.Printf("goctl-validate: warning - imported API file not found: %s\n", importPath)
-	return ""
-}
-
-// dirExists 检查目录是否存在
-func dirExists(dirname string) bool {
-	info, err := os.Stat(dirname)
-	return !os.IsNotExist(err) && info.IsDir()
+	return importPath, nil
 }
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// partialTypes JSONFields测试使用的类型定义
+const partialTypes = `package main
+
+type Address struct {
+	City string ` + "`json:\"city\" validate:\"required\"`" + `
+	Zip  string ` + "`json:\"zip\"`" + `
+}
+
+type Base struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}
+
+type Item struct {
+	Name string ` + "`json:\"name\" validate:\"required\"`" + `
+}
+
+type Req struct {
+	Base
+	Name   string   ` + "`json:\"name\" validate:\"required\"`" + `
+	Nick   string   ` + "`json:\"nick,optional\" validate:\"omitempty,min=2\"`" + `
+	Addr   *Address ` + "`json:\"addr\"`" + `
+	Items  []Item   ` + "`json:\"items\" validate:\"dive\"`" + `
+	Secret string   ` + "`json:\"-\"`" + `
+	Note   string
+}
+`
+
+// partialMain 对每个请求体输出JSONFields的结果，以及ValidatePartial和Validate是否通过，
+// 最后输出v不是结构体时JSONFields返回的错误
+const partialMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func main() {
+	bodies := []string{
+		` + "`{\"name\":\"a\"}`" + `,
+		` + "`{\"nick\":\"nn\",\"id\":1}`" + `,
+		` + "`{\"addr\":{\"city\":\"x\"},\"items\":[{\"name\":\"a\"},{\"name\":\"\"}]}`" + `,
+		` + "`{\"Note\":\"x\",\"-\":1,\"Secret\":\"s\"}`" + `,
+		` + "`{\"nick\":\"n\"}`" + `,
+		` + "`[1]`" + `,
+	}
+	for _, body := range bodies {
+		req := &Req{}
+		fields, err := JSONFields([]byte(body), req)
+		if err != nil {
+			fmt.Println("error")
+			continue
+		}
+		if err := json.Unmarshal([]byte(body), req); err != nil {
+			panic(err)
+		}
+		fmt.Println(fields, req.ValidatePartial(fields...) == nil, req.Validate() == nil)
+	}
+
+	for _, v := range []interface{}{nil, 1, &[]Req{}} {
+		_, err := JSONFields([]byte(` + "`{}`" + `), v)
+		fmt.Println(err)
+	}
+}
+`
+
+func TestJSONFields(t *testing.T) {
+	skipWithoutGo(t)
+	logOutput = io.Discard
+	defer func() { logOutput = os.Stdout }()
+
+	structs := []ValidateStruct{
+		{Name: "Address", Fields: []ValidateField{newField("City", "string", "required")}},
+		{Name: "Item", Fields: []ValidateField{newField("Name", "string", "required")}},
+		{Name: "Req", Fields: []ValidateField{
+			newField("Name", "string", "required"),
+			newField("Nick", "string", "omitempty,min=2"),
+			newField("Addr", "*Address", ""),
+			newField("Items", "[]Item", "dive"),
+		}},
+	}
+
+	dir := t.TempDir()
+	g := NewValidateGenerator(&plugin.Plugin{Dir: dir, ApiFilePath: filepath.Join(dir, "test.api")}, &Options{Package: "main"})
+	files, err := g.renderFiles(dir, structs, nil, &manifest{}, &staticCode{})
+	if err != nil {
+		t.Fatalf("render files: %v", err)
+	}
+
+	output, err := runGeneratedCode(t, dir, files, partialTypes, partialMain)
+	if err != nil {
+		t.Fatalf("run generated code: %v\n%s", err, output)
+	}
+
+	// 只验证请求体中出现的字段：只提交nick时不要求name，提交了的字段仍然按规则验证
+	want := `[Name] true true
+[Base.ID Nick] true false
+[Addr Addr.City Items Items[0] Items[0].Name Items[1] Items[1].Name] false false
+[Note] true false
+[Nick] false false
+error
+JSONFields: <nil> is not a struct or a pointer to struct
+JSONFields: int is not a struct or a pointer to struct
+JSONFields: *[]main.Req is not a struct or a pointer to struct
+`
+	if string(output) != want {
+		t.Errorf("got\n%s\nwant\n%s", output, want)
+	}
+}
@@ -0,0 +1,274 @@
+package generator
+
+import "strings"
+
+// paramKind 规则参数的形式
+type paramKind int
+
+const (
+	paramNone       paramKind = iota // 不接受参数，例如 required、email
+	paramNumber                      // 必须是数字，例如 min=3、len=11
+	paramOptional                    // 参数可选，例如 gt、unique
+	paramValue                       // 必须有参数，内容不限，例如 contains=abc、eq=abc
+	paramList                        // 空格分隔的取值列表，例如 oneof=0 1 2
+	paramField                       // 同一结构体（或顶层结构体）的字段名，例如 eqfield=Password
+	paramFieldValue                  // 字段名与取值成对出现，例如 required_if=Type 2
+	paramFields                      // 空格分隔的字段名列表，例如 required_with=Phone Email
+)
+
+// typeClass 规则适用的字段类型，按位组合
+type typeClass int
+
+const (
+	classString typeClass = 1 << iota
+	classNumber
+	classBool
+	classCollection // slice、array和map
+	classStruct
+	classAny = classString | classNumber | classBool | classCollection | classStruct
+)
+
+// ruleSpec validator规则的参数形式和适用类型
+type ruleSpec struct {
+	Param paramKind
+	Types typeClass
+}
+
+// sizeTypes 长度或大小相关规则适用的类型：字符串长度、数值大小、集合元素个数
+const sizeTypes = classString | classNumber | classCollection
+
+// knownRules go-playground/validator v10内置的规则
+var knownRules = map[string]ruleSpec{
+	// 特殊规则
+	"omitempty":     {paramNone, classAny},
+	"omitnil":       {paramNone, classAny},
+	"required":      {paramNone, classAny},
+	"isdefault":     {paramNone, classAny},
+	"structonly":    {paramNone, classStruct},
+	"nostructlevel": {paramNone, classStruct},
+
+	// 条件必填
+	"required_if":          {paramFieldValue, classAny},
+	"required_unless":      {paramFieldValue, classAny},
+	"skip_unless":          {paramFieldValue, classAny},
+	"required_with":        {paramFields, classAny},
+	"required_with_all":    {paramFields, classAny},
+	"required_without":     {paramFields, classAny},
+	"required_without_all": {paramFields, classAny},
+	"excluded_if":          {paramFieldValue, classAny},
+	"excluded_unless":      {paramFieldValue, classAny},
+	"excluded_with":        {paramFields, classAny},
+	"excluded_with_all":    {paramFields, classAny},
+	"excluded_without":     {paramFields, classAny},
+	"excluded_without_all": {paramFields, classAny},
+
+	// 比较
+	"len":            {paramNumber, sizeTypes},
+	"min":            {paramNumber, sizeTypes},
+	"max":            {paramNumber, sizeTypes},
+	"eq":             {paramValue, classAny},
+	"ne":             {paramValue, classAny},
+	"eq_ignore_case": {paramValue, classString},
+	"ne_ignore_case": {paramValue, classString},
+	"gt":             {paramOptional, sizeTypes | classStruct},
+	"gte":            {paramOptional, sizeTypes | classStruct},
+	"lt":             {paramOptional, sizeTypes | classStruct},
+	"lte":            {paramOptional, sizeTypes | classStruct},
+	"oneof":          {paramList, classString | classNumber},
+	"unique":         {paramOptional, classCollection},
+
+	// 字段比较
+	"eqfield":       {paramField, classAny},
+	"nefield":       {paramField, classAny},
+	"gtfield":       {paramField, classAny},
+	"gtefield":      {paramField, classAny},
+	"ltfield":       {paramField, classAny},
+	"ltefield":      {paramField, classAny},
+	"eqcsfield":     {paramField, classAny},
+	"necsfield":     {paramField, classAny},
+	"gtcsfield":     {paramField, classAny},
+	"gtecsfield":    {paramField, classAny},
+	"ltcsfield":     {paramField, classAny},
+	"ltecsfield":    {paramField, classAny},
+	"fieldcontains": {paramField, classString},
+	"fieldexcludes": {paramField, classString},
+
+	// 字符串内容
+	"alpha":           {paramNone, classString},
+	"alphanum":        {paramNone, classString},
+	"alphaunicode":    {paramNone, classString},
+	"alphanumunicode": {paramNone, classString},
+	"ascii":           {paramNone, classString},
+	"printascii":      {paramNone, classString},
+	"multibyte":       {paramNone, classString},
+	"boolean":         {paramNone, classString | classBool},
+	"numeric":         {paramNone, classString | classNumber},
+	"number":          {paramNone, classString | classNumber},
+	"hexadecimal":     {paramNone, classString},
+	"lowercase":       {paramNone, classString},
+	"uppercase":       {paramNone, classString},
+	"contains":        {paramValue, classString},
+	"containsany":     {paramValue, classString},
+	"containsrune":    {paramValue, classString},
+	"excludes":        {paramValue, classString},
+	"excludesall":     {paramValue, classString},
+	"excludesrune":    {paramValue, classString},
+	"startswith":      {paramValue, classString},
+	"endswith":        {paramValue, classString},
+	"startsnotwith":   {paramValue, classString},
+	"endsnotwith":     {paramValue, classString},
+
+	// 格式
+	"email":                         {paramNone, classString},
+	"url":                           {paramNone, classString},
+	"http_url":                      {paramNone, classString},
+	"uri":                           {paramNone, classString},
+	"urn_rfc2141":                   {paramNone, classString},
+	"url_encoded":                   {paramNone, classString},
+	"file":                          {paramNone, classString},
+	"filepath":                      {paramNone, classString},
+	"dir":                           {paramNone, classString},
+	"dirpath":                       {paramNone, classString},
+	"image":                         {paramNone, classString},
+	"base64":                        {paramNone, classString},
+	"base64url":                     {paramNone, classString},
+	"base64rawurl":                  {paramNone, classString},
+	"datauri":                       {paramNone, classString},
+	"json":                          {paramNone, classString},
+	"jwt":                           {paramNone, classString},
+	"html":                          {paramNone, classString},
+	"html_encoded":                  {paramNone, classString},
+	"datetime":                      {paramValue, classString},
+	"timezone":                      {paramNone, classString},
+	"e164":                          {paramNone, classString},
+	"hexcolor":                      {paramNone, classString},
+	"rgb":                           {paramNone, classString},
+	"rgba":                          {paramNone, classString},
+	"hsl":                           {paramNone, classString},
+	"hsla":                          {paramNone, classString},
+	"iscolor":                       {paramNone, classString},
+	"uuid":                          {paramNone, classString},
+	"uuid3":                         {paramNone, classString},
+	"uuid4":                         {paramNone, classString},
+	"uuid5":                         {paramNone, classString},
+	"uuid_rfc4122":                  {paramNone, classString},
+	"uuid3_rfc4122":                 {paramNone, classString},
+	"uuid4_rfc4122":                 {paramNone, classString},
+	"uuid5_rfc4122":                 {paramNone, classString},
+	"ulid":                          {paramNone, classString},
+	"md4":                           {paramNone, classString},
+	"md5":                           {paramNone, classString},
+	"sha256":                        {paramNone, classString},
+	"sha384":                        {paramNone, classString},
+	"sha512":                        {paramNone, classString},
+	"ripemd128":                     {paramNone, classString},
+	"ripemd160":                     {paramNone, classString},
+	"tiger128":                      {paramNone, classString},
+	"tiger160":                      {paramNone, classString},
+	"tiger192":                      {paramNone, classString},
+	"isbn":                          {paramNone, classString},
+	"isbn10":                        {paramNone, classString},
+	"isbn13":                        {paramNone, classString},
+	"issn":                          {paramNone, classString},
+	"eth_addr":                      {paramNone, classString},
+	"eth_addr_checksum":             {paramNone, classString},
+	"btc_addr":                      {paramNone, classString},
+	"btc_addr_bech32":               {paramNone, classString},
+	"latitude":                      {paramNone, classString | classNumber},
+	"longitude":                     {paramNone, classString | classNumber},
+	"ssn":                           {paramNone, classString},
+	"ip":                            {paramNone, classString},
+	"ipv4":                          {paramNone, classString},
+	"ipv6":                          {paramNone, classString},
+	"cidr":                          {paramNone, classString},
+	"cidrv4":                        {paramNone, classString},
+	"cidrv6":                        {paramNone, classString},
+	"tcp_addr":                      {paramNone, classString},
+	"tcp4_addr":                     {paramNone, classString},
+	"tcp6_addr":                     {paramNone, classString},
+	"udp_addr":                      {paramNone, classString},
+	"udp4_addr":                     {paramNone, classString},
+	"udp6_addr":                     {paramNone, classString},
+	"ip_addr":                       {paramNone, classString},
+	"ip4_addr":                      {paramNone, classString},
+	"ip6_addr":                      {paramNone, classString},
+	"unix_addr":                     {paramNone, classString},
+	"mac":                           {paramNone, classString},
+	"hostname":                      {paramNone, classString},
+	"hostname_rfc1123":              {paramNone, classString},
+	"hostname_port":                 {paramNone, classString},
+	"fqdn":                          {paramNone, classString},
+	"dns_rfc1035_label":             {paramNone, classString},
+	"iso3166_1_alpha2":              {paramNone, classString},
+	"iso3166_1_alpha3":              {paramNone, classString},
+	"iso3166_1_alpha_numeric":       {paramNone, classNumber},
+	"iso3166_2":                     {paramNone, classString},
+	"iso4217":                       {paramNone, classString},
+	"iso4217_numeric":               {paramNone, classNumber},
+	"country_code":                  {paramNone, classString | classNumber},
+	"bcp47_language_tag":            {paramNone, classString},
+	"postcode_iso3166_alpha2":       {paramValue, classString},
+	"postcode_iso3166_alpha2_field": {paramField, classString},
+	"bic":                           {paramNone, classString},
+	"semver":                        {paramNone, classString},
+	"credit_card":                   {paramNone, classString},
+	"cve":                           {paramNone, classString},
+	"luhn_checksum":                 {paramNone, classString | classNumber},
+	"mongodb":                       {paramNone, classString},
+	"cron":                          {paramNone, classString},
+	"spicedb":                       {paramValue, classString},
+}
+
+// numberTypes 数值类型
+var numberTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "byte": true, "rune": true,
+}
+
+// classOf 返回字段类型所属的类别，无法判断时（interface{}、外部包类型）返回classAny
+func classOf(fieldType *FieldType) typeClass {
+	t := fieldType.Deref()
+	if t == nil {
+		return classAny
+	}
+
+	switch t.Kind {
+	case KindBasic:
+		switch {
+		case t.Name == "string":
+			return classString
+		case t.Name == "bool":
+			return classBool
+		case numberTypes[t.Name]:
+			return classNumber
+		}
+	case KindSlice, KindArray, KindMap:
+		return classCollection
+	case KindStruct:
+		return classStruct
+	case KindNamed:
+		// 外部包的类型（如time.Time、time.Duration）无法判断
+		if !strings.Contains(t.Name, ".") {
+			return classStruct
+		}
+	}
+	return classAny
+}
+
+// className 类别的可读名称，用于错误提示
+func className(class typeClass) string {
+	switch class {
+	case classString:
+		return "string"
+	case classNumber:
+		return "number"
+	case classBool:
+		return "bool"
+	case classCollection:
+		return "slice/array/map"
+	case classStruct:
+		return "struct"
+	}
+	return "any"
+}
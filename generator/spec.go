@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// parseSpecForValidateStructs 从goctl已解析的ApiSpec中获取所有结构体及其字段
+// 与types.go使用同一份解析结果，保证goctl能识别的语法插件也能识别
+func parseSpecForValidateStructs(api *spec.ApiSpec) ([]ValidateStruct, error) {
+	var allStructs []ValidateStruct
+
+	for _, t := range api.Types {
+		defineStruct, ok := t.(spec.DefineStruct)
+		if !ok {
+			continue
+		}
+
+		validateStruct := ValidateStruct{
+			Name:   defineStruct.RawName,
+			Fields: []ValidateField{},
+		}
+
+		for _, member := range defineStruct.Members {
+			field, err := convertMember(member)
+			if err != nil {
+				return nil, fmt.Errorf("struct %s: %v", defineStruct.RawName, err)
+			}
+			if field != nil {
+				validateStruct.Fields = append(validateStruct.Fields, *field)
+			}
+		}
+
+		allStructs = append(allStructs, validateStruct)
+	}
+
+	logf("found %d structures in api spec", len(allStructs))
+	return allStructs, nil
+}
+
+// convertMember 将ApiSpec的字段转换为ValidateField
+// 没有validate标签的字段同样保留，用于分析嵌套类型的引用关系
+func convertMember(member spec.Member) (*ValidateField, error) {
+	fieldType, err := convertSpecType(member.Type)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %v", member.Name, err)
+	}
+
+	// 内嵌字段没有字段名，Go以类型名作为字段名
+	if member.IsInline || member.Name == "" {
+		return &ValidateField{
+			Name:     fieldType.Deref().Name,
+			Type:     fieldType,
+			Embedded: true,
+		}, nil
+	}
+
+	field := &ValidateField{
+		Name: member.Name,
+		Type: fieldType,
+	}
+
+	// 内联匿名结构体，递归转换其字段
+	if nested, ok := member.Type.(spec.NestedStruct); ok {
+		for _, nestedMember := range nested.Members {
+			nestedField, err := convertMember(nestedMember)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", member.Name, err)
+			}
+			if nestedField != nil {
+				field.Fields = append(field.Fields, *nestedField)
+			}
+		}
+	}
+
+	if member.Tag == "" {
+		return field, nil
+	}
+
+	tags, err := spec.Parse(member.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag on field %s: %v", member.Name, err)
+	}
+
+	field.ValidateRule = tagValue(tags, "validate")
+	field.JsonTag = tagValue(tags, "json")
+	return field, nil
+}
+
+// tagValue 获取指定key的完整标签值（包含选项），例如 "required,min=3"
+func tagValue(tags *spec.Tags, key string) string {
+	tag, err := tags.Get(key)
+	if err != nil {
+		return ""
+	}
+
+	value := tag.Name
+	for _, option := range tag.Options {
+		value += "," + option
+	}
+	return value
+}
+
+// locateSpecPositions 通过行扫描器定位ApiSpec中结构体和字段在API文件中的位置
+// ApiSpec本身不包含位置信息，定位失败时位置保持为空，不影响代码生成
+func locateSpecPositions(apiFilePath string, structs []ValidateStruct) {
+	s := &apiScanner{
+		processedFiles: make(map[string]bool),
+	}
+	if err := s.parseAPIFileRecursively(apiFilePath); err != nil {
+		return
+	}
+
+	scanned := make(map[string]ValidateStruct, len(s.structs))
+	for _, validateStruct := range s.structs {
+		scanned[validateStruct.Name] = validateStruct
+	}
+
+	for i := range structs {
+		scannedStruct, ok := scanned[structs[i].Name]
+		if !ok {
+			continue
+		}
+		structs[i].File = scannedStruct.File
+		structs[i].Line = scannedStruct.Line
+		locateFields(structs[i].Fields, scannedStruct.Fields)
+	}
+}
+
+// locateFields 按字段名复制扫描到的字段位置，内联结构体递归处理
+func locateFields(fields, scannedFields []ValidateField) {
+	scanned := make(map[string]ValidateField, len(scannedFields))
+	for _, field := range scannedFields {
+		scanned[field.Name] = field
+	}
+
+	for i := range fields {
+		scannedField, ok := scanned[fields[i].Name]
+		if !ok {
+			continue
+		}
+		fields[i].File = scannedField.File
+		fields[i].Line = scannedField.Line
+		locateFields(fields[i].Fields, scannedField.Fields)
+	}
+}
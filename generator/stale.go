@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// staleFile 之前生成、本次不再生成的文件
+type staleFile struct {
+	Path      string
+	Removable bool // 带有生成标记或者是清单文件，可以直接删除；否则可能包含用户代码，只提示
+}
+
+// staleFiles 找出输出目录中不再生成的旧文件：清单中记录的文件，以及按照当前style命名的
+// validate.go、validate_custom.go、translator.go、translator_custom.go（兼容没有清单的旧版本）
+func (g *ValidateGenerator) staleFiles(outputDir string, previous *manifest, files []outputFile) []staleFile {
+	produced := make(map[string]bool, len(files))
+	for _, file := range files {
+		produced[filepath.Base(file.Path)] = true
+	}
+
+	owned := make(map[string]bool)
+	candidates := append([]string{}, previous.Files...)
+	for _, name := range previous.Files {
+		owned[name] = true
+	}
+	for _, name := range []string{"validate", "validate_custom", "translator", "translator_custom"} {
+		path, err := g.outputFileName(outputDir, name)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, filepath.Base(path))
+		// 自定义文件由插件创建，没有清单记录时也属于插件
+		if name == "translator_custom" || name == "validate_custom" {
+			owned[filepath.Base(path)] = true
+		}
+	}
+	// 没有任何输出时清单本身也不再需要
+	if len(files) == 0 {
+		candidates = append(candidates, manifestFile)
+	}
+
+	var stale []staleFile
+	seen := make(map[string]bool)
+	for _, name := range candidates {
+		if produced[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		path := filepath.Join(outputDir, name)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case name == manifestFile || hasGeneratedMarker(string(content)):
+			stale = append(stale, staleFile{Path: path, Removable: true})
+		case owned[name]:
+			// 用户可编辑的文件，例如关闭翻译器后的translator_custom.go、没有需要验证的结构体时的validate_custom.go
+			stale = append(stale, staleFile{Path: path})
+			g.warnf("%s is no longer used by the generated code, remove it manually (it may contain your custom code)", path)
+		}
+	}
+	return stale
+}
+
+// removeStaleFiles 删除不再生成的文件
+func removeStaleFiles(stale []staleFile) error {
+	for _, file := range stale {
+		if !file.Removable {
+			continue
+		}
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		logf("removed stale file %s", file.Path)
+	}
+	return nil
+}
+
+// reportRemovedTypes 输出不再生成验证方法的类型，调用方需要同步修改；
+// current包含本次生成的类型以及手写了验证方法的类型
+func (g *ValidateGenerator) reportRemovedTypes(previous, current []string) {
+	exists := make(map[string]bool, len(current))
+	for _, name := range current {
+		exists[name] = true
+	}
+	for _, name := range previous {
+		if !exists[name] {
+			logf("%s no longer has a %s method, update code calling %s.%s()", name, g.options.Method, name, g.options.Method)
+		}
+	}
+}
+
+// Clean 删除插件在输出目录中生成的所有文件和清单，可能包含用户代码的文件只提示不删除
+func (g *ValidateGenerator) Clean() error {
+	outputDir := g.outputDir()
+	previous, err := loadManifest(outputDir)
+	if err != nil {
+		return err
+	}
+
+	stale := g.staleFiles(outputDir, previous, nil)
+	if len(stale) == 0 {
+		logf("nothing to clean in %s", outputDir)
+		return nil
+	}
+	return removeStaleFiles(stale)
+}
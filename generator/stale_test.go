@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// writeOutputDir 在输出目录中创建文件，返回输出目录
+func writeOutputDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// staleNames 返回过期文件的文件名，不可删除的文件以 ! 结尾
+func staleNames(stale []staleFile) []string {
+	var names []string
+	for _, file := range stale {
+		name := filepath.Base(file.Path)
+		if !file.Removable {
+			name += "!"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestStaleFiles(t *testing.T) {
+	generated := generatedMarker + ". DO NOT EDIT.\n\npackage types\n"
+	dir := writeOutputDir(t, map[string]string{
+		"validate.go":          generated,
+		"translator.go":        generated,
+		"translator_custom.go": "package types\n",
+		"partial.go":           generated,
+		"notes.go":             "package types\n",
+		manifestFile:           "{}\n",
+	})
+
+	g := NewValidateGenerator(&plugin.Plugin{Dir: dir}, &Options{OutputDir: dir})
+	previous := &manifest{Files: []string{"validate.go", "partial.go", "translator.go"}}
+	files := []outputFile{{Path: filepath.Join(dir, "validate.go"), Content: generated, Generated: true}}
+
+	// 关闭翻译器后translator.go可以删除，translator_custom.go可能包含用户代码只提示，
+	// 清单之外的手写文件不受影响
+	want := []string{"partial.go", "translator.go", "translator_custom.go!"}
+	if got := staleNames(g.staleFiles(dir, previous, files)); !reflect.DeepEqual(got, want) {
+		t.Errorf("stale files %v, want %v", got, want)
+	}
+	if g.warnings != 1 {
+		t.Errorf("got %d warnings, want 1 for translator_custom.go", g.warnings)
+	}
+
+	// 没有任何输出时清单也不再需要
+	want = []string{manifestFile, "partial.go", "translator.go", "translator_custom.go!", "validate.go"}
+	if got := staleNames(g.staleFiles(dir, previous, nil)); !reflect.DeepEqual(got, want) {
+		t.Errorf("stale files without outputs %v, want %v", got, want)
+	}
+}
+
+func TestClean(t *testing.T) {
+	generated := generatedMarker + ". DO NOT EDIT.\n\npackage types\n"
+	dir := writeOutputDir(t, map[string]string{
+		"validate.go":          generated,
+		"translator_custom.go": "package types\n",
+		"types.go":             "package types\n",
+	})
+	content, err := (&manifest{Files: []string{"validate.go"}}).encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewValidateGenerator(&plugin.Plugin{Dir: dir}, &Options{OutputDir: dir})
+	if err := g.Clean(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	if want := []string{"translator_custom.go", "types.go"}; !reflect.DeepEqual(remaining, want) {
+		t.Errorf("remaining files %v, want %v", remaining, want)
+	}
+
+	// 再次清理时没有需要删除的文件
+	if err := g.Clean(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,642 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StaticRegexp 静态模式下预编译的正则表达式。表达式是validator对应规则可接受内容的子集：
+// 匹配成功时validator一定验证通过，匹配失败时交给validator判断，不会改变验证结果
+type StaticRegexp struct {
+	Name    string
+	Pattern string
+}
+
+// staticRegexps 支持静态检查的字符串格式规则
+var staticRegexps = map[string]StaticRegexp{
+	"email":    {"staticEmailRegexp", `^[a-zA-Z0-9_%+-]+(?:\.[a-zA-Z0-9_%+-]+)*@(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?\.)+[a-zA-Z](?:[a-zA-Z0-9-]*[a-zA-Z])?$`},
+	"url":      {"staticURLRegexp", `^(?i)https?://[a-z0-9](?:[a-z0-9.-]*[a-z0-9])?(?::[0-9]{1,5})?(?:[/?#][a-z0-9._~!$&'()*+,;=:@/?#-]*)?$`},
+	"alpha":    {"staticAlphaRegexp", `^[a-zA-Z]+$`},
+	"alphanum": {"staticAlphanumRegexp", `^[a-zA-Z0-9]+$`},
+	"numeric":  {"staticNumericRegexp", `^[-+]?[0-9]+(?:\.[0-9]+)?$`},
+	"number":   {"staticNumberRegexp", `^[0-9]+$`},
+}
+
+// staticSamples 格式规则在基准测试中使用的示例值
+var staticSamples = map[string]string{
+	"email": "user@example.com",
+	"url":   "https://example.com",
+}
+
+// intBits 整数类型的位数，int、uint按32位处理，保证生成的常量在所有平台上都不会溢出
+var intBits = map[string]int{
+	"int": 32, "int8": 8, "int16": 16, "int32": 32, "int64": 64, "rune": 32,
+	"uint": 32, "uint8": 8, "byte": 8, "uint16": 16, "uint32": 32, "uint64": 64, "uintptr": 32,
+}
+
+// staticCode 静态模式下生成的检查代码
+type staticCode struct {
+	Checks  map[string][]string // 结构体名 -> 检查条件，条件为true表示可能不合法
+	Samples map[string]string   // 结构体名 -> 基准测试使用的合法示例值
+	Imports []string            // validate.go 额外导入的包
+	Regexps []StaticRegexp      // 使用到的预编译正则表达式
+}
+
+// staticBuilder 将validate规则转换为不使用反射的Go代码
+type staticBuilder struct {
+	graph    *typeGraph
+	receiver string
+	imports  map[string]bool
+	regexps  map[string]bool
+}
+
+// staticField 字段的类型类别和规则
+type staticField struct {
+	expr    string // 字段的访问表达式，例如 r.Name
+	typ     *FieldType
+	pointer bool // 字段是否为指针，规则作用于指针指向的值
+	class   string
+	rules   []staticRule
+}
+
+// staticRule 一个validate规则
+type staticRule struct {
+	Name  string
+	Param string
+}
+
+// buildStaticCode 为每个结构体生成静态检查，包含无法静态检查的规则的结构体继续使用validator
+func (g *ValidateGenerator) buildStaticCode(graph *typeGraph, validateStructs []ValidateStruct) *staticCode {
+	b := &staticBuilder{
+		graph:    graph,
+		receiver: g.options.Receiver,
+		imports:  make(map[string]bool),
+		regexps:  make(map[string]bool),
+	}
+	code := &staticCode{
+		Checks:  make(map[string][]string),
+		Samples: make(map[string]string),
+	}
+
+	static := 0
+	for _, validateStruct := range validateStructs {
+		checks, err := b.structChecks(validateStruct)
+		if err != nil {
+			logf("%s uses validator in static mode: %v", validateStruct.Name, err)
+			continue
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		code.Checks[validateStruct.Name] = checks
+		if sample, ok := b.structSample(validateStruct); ok {
+			code.Samples[validateStruct.Name] = sample
+		}
+		static++
+	}
+	logf("static mode: %d of %d structures are validated without reflection", static, len(validateStructs))
+
+	for pkg := range b.imports {
+		code.Imports = append(code.Imports, pkg)
+	}
+	sort.Strings(code.Imports)
+	for name := range b.regexps {
+		code.Regexps = append(code.Regexps, staticRegexps[name])
+	}
+	sort.Slice(code.Regexps, func(i, j int) bool {
+		return code.Regexps[i].Name < code.Regexps[j].Name
+	})
+	return code
+}
+
+// structChecks 生成结构体所有字段的检查条件，返回错误表示结构体需要使用validator
+func (b *staticBuilder) structChecks(validateStruct ValidateStruct) ([]string, error) {
+	if _, ok := b.graph.hooks[validateStruct.Name]; ok {
+		return nil, fmt.Errorf("%s method requires the validator", structHookMethod)
+	}
+
+	var checks []string
+	var fields []*staticField
+	for _, field := range validateStruct.Fields {
+		f, err := b.field(field)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if f == nil {
+			continue
+		}
+		check, err := f.check()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if check == "" {
+			continue
+		}
+		checks = append(checks, check)
+		fields = append(fields, f)
+	}
+
+	// 整个结构体都能静态检查时才记录需要的导入和正则表达式
+	for _, f := range fields {
+		for _, rule := range f.rules {
+			if _, ok := staticRegexps[rule.Name]; ok {
+				b.regexps[rule.Name] = true
+				b.imports["regexp"] = true
+			}
+		}
+		if f.class == "string" && f.needsRuneCount() {
+			b.imports["unicode/utf8"] = true
+		}
+	}
+	return checks, nil
+}
+
+// field 解析字段的类型和规则，字段不需要检查时返回nil
+func (b *staticBuilder) field(field ValidateField) (*staticField, error) {
+	rule := strings.TrimSpace(field.ValidateRule)
+	if rule == "-" {
+		return nil, nil
+	}
+
+	// validator会自动深入结构体字段，引用了需要验证的结构体时无法静态检查
+	if field.Type.Deref().Kind == KindStruct {
+		nested := ValidateStruct{Fields: field.Fields}
+		if hasRules(&nested) || rule != "" {
+			return nil, fmt.Errorf("inline struct is not supported")
+		}
+		return nil, nil
+	}
+	if t := field.Type.Deref(); t.Kind == KindNamed {
+		if rule != "" || b.graph.resolve(t.Name) == nil || b.graph.needsValidate(t.Name) {
+			return nil, fmt.Errorf("type %s is not supported", field.Type)
+		}
+		return nil, nil
+	}
+	if rule == "" {
+		return nil, nil
+	}
+	if field.Embedded {
+		return nil, fmt.Errorf("embedded field is not supported")
+	}
+
+	f := &staticField{
+		expr: b.receiver + "." + field.Name,
+		typ:  field.Type,
+	}
+	if f.typ.Kind == KindPointer {
+		f.pointer = true
+		f.typ = f.typ.Elem
+	}
+	f.class = typeClassOf(f.typ)
+	if f.class == "" {
+		return nil, fmt.Errorf("type %s is not supported", field.Type)
+	}
+
+	if strings.Contains(rule, "|") || strings.Contains(rule, "0x2C") || strings.Contains(rule, "0x7C") {
+		return nil, fmt.Errorf("or operator and escaped characters are not supported")
+	}
+	for _, token := range strings.Split(rule, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(token), "=")
+		f.rules = append(f.rules, staticRule{Name: name, Param: param})
+	}
+	return f, nil
+}
+
+// typeClassOf 字段类型的类别，不支持的类型返回空字符串
+func typeClassOf(t *FieldType) string {
+	switch t.Kind {
+	case KindBasic:
+		switch {
+		case t.Name == "string":
+			return "string"
+		case t.Name == "bool":
+			return "bool"
+		case t.Name == "float32" || t.Name == "float64":
+			return "float"
+		case strings.HasPrefix(t.Name, "uint") || t.Name == "byte":
+			return "uint"
+		case intBits[t.Name] > 0:
+			return "int"
+		}
+	case KindSlice, KindMap:
+		return "collection"
+	}
+	return ""
+}
+
+// needsRuneCount 字符串长度规则按字符数计算，需要导入unicode/utf8
+func (f *staticField) needsRuneCount() bool {
+	for _, rule := range f.rules {
+		switch rule.Name {
+		case "len", "min", "max", "gt", "gte", "lt", "lte":
+			return true
+		}
+	}
+	return false
+}
+
+// value 规则作用的值的表达式
+func (f *staticField) value() string {
+	if f.pointer {
+		return "*" + f.expr
+	}
+	return f.expr
+}
+
+// size 长度规则比较的表达式：字符串的字符数、集合的元素个数或者数值本身
+func (f *staticField) size() string {
+	switch f.class {
+	case "string":
+		return "utf8.RuneCountInString(" + f.value() + ")"
+	case "collection":
+		return "len(" + f.value() + ")"
+	}
+	return f.value()
+}
+
+// zero 值为零值（或nil）的条件，与validator的omitempty、required一致：
+// 指针、切片、map只判断是否为nil，validator会先解引用指针，指向切片、map的指针还要判断指向的值是否为nil
+func (f *staticField) zero() string {
+	if f.pointer {
+		if f.class == "collection" {
+			return f.expr + " == nil || *" + f.expr + " == nil"
+		}
+		return f.expr + " == nil"
+	}
+	switch f.class {
+	case "string":
+		return f.expr + ` == ""`
+	case "bool":
+		return "!" + f.expr
+	case "collection":
+		return f.expr + " == nil"
+	}
+	return f.expr + " == 0"
+}
+
+// nonzero 值不为零值（或nil）的条件
+func (f *staticField) nonzero() string {
+	if f.pointer {
+		if f.class == "collection" {
+			return f.expr + " != nil && *" + f.expr + " != nil"
+		}
+		return f.expr + " != nil"
+	}
+	switch f.class {
+	case "string":
+		return f.expr + ` != ""`
+	case "bool":
+		return f.expr
+	case "collection":
+		return f.expr + " != nil"
+	}
+	return f.expr + " != 0"
+}
+
+// check 生成字段的检查条件，条件为true表示字段可能不合法，没有需要检查的规则时返回空字符串
+func (f *staticField) check() (string, error) {
+	rules := f.rules
+	omitempty := len(rules) > 0 && rules[0].Name == "omitempty"
+	if omitempty {
+		rules = rules[1:]
+	}
+
+	var conds []string
+	for _, rule := range rules {
+		cond, err := f.ruleCond(rule)
+		if err != nil {
+			return "", err
+		}
+		if cond != "" {
+			conds = append(conds, cond)
+		}
+	}
+
+	switch {
+	case f.pointer && !omitempty:
+		// 没有omitempty的nil指针一定验证失败
+		return strings.Join(append([]string{f.expr + " == nil"}, conds...), " || "), nil
+	case len(conds) == 0:
+		return "", nil
+	case omitempty:
+		// 零值（nil指针）跳过所有规则，指针非nil时规则作用于指向的值
+		return f.nonzero() + " && " + anyCond(conds), nil
+	}
+	return strings.Join(conds, " || "), nil
+}
+
+// anyCond 任意一个条件成立，多个条件时加上括号
+func anyCond(conds []string) string {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return "(" + strings.Join(conds, " || ") + ")"
+}
+
+// ruleCond 生成单个规则不满足的条件
+func (f *staticField) ruleCond(rule staticRule) (string, error) {
+	if rule.Name == "omitempty" {
+		return "", fmt.Errorf("omitempty must be the first rule")
+	}
+	if f.class == "collection" && rule.Name != "required" && !isSizeRule(rule.Name) {
+		return "", fmt.Errorf("rule %s is not supported on %s", rule.Name, f.typ)
+	}
+
+	switch rule.Name {
+	case "required":
+		if f.pointer && f.class != "collection" {
+			// 指针只要求不为nil，已经包含在指针的检查中
+			return "", nil
+		}
+		if f.pointer {
+			// 指向切片、map的指针不为nil时，validator还要求指向的值不为nil
+			return "*" + f.expr + " == nil", nil
+		}
+		return f.zero(), nil
+	case "len", "min", "max", "gt", "gte", "lt", "lte":
+		return f.compareCond(rule)
+	case "eq", "ne":
+		if f.class == "collection" {
+			return f.compareCond(rule)
+		}
+		literal, err := f.literal(rule.Param)
+		if err != nil {
+			return "", err
+		}
+		op := " != "
+		if rule.Name == "ne" {
+			op = " == "
+		}
+		return f.value() + op + literal, nil
+	case "oneof":
+		if f.class != "string" && f.class != "int" && f.class != "uint" {
+			return "", fmt.Errorf("rule oneof is not supported on %s", f.typ)
+		}
+		if strings.ContainsAny(rule.Param, `'"`) {
+			return "", fmt.Errorf("quoted oneof values are not supported")
+		}
+		var conds []string
+		for _, value := range strings.Fields(rule.Param) {
+			literal, err := f.literal(value)
+			if err != nil {
+				return "", err
+			}
+			// validator将整数格式化为十进制后按字符串比较，0x1、010、+1、1_0 这样的取值永远不会匹配
+			if f.class != "string" && literal != value {
+				return "", fmt.Errorf("oneof value %q is not a canonical decimal integer", value)
+			}
+			conds = append(conds, f.value()+" != "+literal)
+		}
+		if len(conds) == 0 {
+			return "", fmt.Errorf("rule oneof has no values")
+		}
+		return "(" + strings.Join(conds, " && ") + ")", nil
+	}
+
+	if re, ok := staticRegexps[rule.Name]; ok && f.class == "string" && rule.Param == "" {
+		return "!" + re.Name + ".MatchString(" + f.value() + ")", nil
+	}
+	return "", fmt.Errorf("rule %s is not supported", rule.Name)
+}
+
+// isSizeRule 比较长度或大小的规则
+func isSizeRule(name string) bool {
+	switch name {
+	case "len", "min", "max", "eq", "ne", "gt", "gte", "lt", "lte":
+		return true
+	}
+	return false
+}
+
+// compareOps 长度规则不满足时的比较运算符
+var compareOps = map[string]string{
+	"len": " != ", "eq": " != ", "ne": " == ",
+	"min": " < ", "gte": " < ", "gt": " <= ",
+	"max": " > ", "lte": " > ", "lt": " >= ",
+}
+
+// compareCond 长度或大小比较的条件，字符串和集合的参数是长度，数值的参数与字段类型相同
+func (f *staticField) compareCond(rule staticRule) (string, error) {
+	if rule.Param == "" {
+		return "", fmt.Errorf("rule %s without parameter is not supported", rule.Name)
+	}
+
+	var literal string
+	var err error
+	if f.class == "string" || f.class == "collection" {
+		literal, err = intLiteral(rule.Param, 32)
+	} else {
+		literal, err = f.literal(rule.Param)
+	}
+	if err != nil {
+		return "", err
+	}
+	return f.size() + compareOps[rule.Name] + literal, nil
+}
+
+// literal 将规则参数转换为与字段类型匹配的Go常量，常量超出类型范围时返回错误
+func (f *staticField) literal(param string) (string, error) {
+	switch f.class {
+	case "string":
+		return strconv.Quote(param), nil
+	case "bool":
+		b, err := strconv.ParseBool(param)
+		if err != nil {
+			return "", fmt.Errorf("invalid bool parameter %q", param)
+		}
+		return strconv.FormatBool(b), nil
+	case "int":
+		return intLiteral(param, intBits[f.typ.Name])
+	case "uint":
+		v, err := strconv.ParseUint(param, 0, intBits[f.typ.Name])
+		if err != nil {
+			return "", fmt.Errorf("parameter %q is out of range for %s", param, f.typ)
+		}
+		return strconv.FormatUint(v, 10), nil
+	case "float":
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return "", fmt.Errorf("invalid number parameter %q", param)
+		}
+		if f.typ.Name == "float32" && math.Abs(v) > math.MaxFloat32 {
+			return "", fmt.Errorf("parameter %q is out of range for %s", param, f.typ)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("type %s is not supported", f.typ)
+}
+
+// intLiteral 解析整数参数，超出位数范围时返回错误
+func intLiteral(param string, bits int) (string, error) {
+	v, err := strconv.ParseInt(param, 0, bits)
+	if err != nil {
+		return "", fmt.Errorf("parameter %q is not a valid %d-bit integer", param, bits)
+	}
+	return strconv.FormatInt(v, 10), nil
+}
+
+// structSample 生成满足所有规则的结构体示例值，用于基准测试，无法生成时返回false
+func (b *staticBuilder) structSample(validateStruct ValidateStruct) (string, bool) {
+	var values []string
+	for _, field := range validateStruct.Fields {
+		f, err := b.field(field)
+		if err != nil {
+			return "", false
+		}
+		if f == nil || f.rules[0].Name == "omitempty" {
+			// 零值满足omitempty，不需要赋值
+			continue
+		}
+		value, ok := f.sample()
+		if !ok {
+			return "", false
+		}
+		values = append(values, field.Name+": "+value)
+	}
+	return "&" + validateStruct.Name + "{" + strings.Join(values, ", ") + "}", true
+}
+
+// sample 满足字段规则的示例值
+func (f *staticField) sample() (string, bool) {
+	var value string
+	var ok bool
+	switch f.class {
+	case "string":
+		value, ok = f.stringSample()
+	case "int", "uint", "float":
+		value, ok = f.numberSample()
+	case "bool":
+		value, ok = "true", true
+		for _, rule := range f.rules {
+			if rule.Name == "eq" && rule.Param == "false" {
+				value = "false"
+			}
+		}
+	case "collection":
+		value, ok = f.collectionSample()
+	}
+	if !ok || !f.pointer {
+		return value, ok
+	}
+	return fmt.Sprintf("func() *%s { v := %s(%s); return &v }()", f.typ, f.typ, value), true
+}
+
+// bounds 长度或大小规则限定的范围
+func (f *staticField) bounds() (lower, upper float64) {
+	lower, upper = math.Inf(-1), math.Inf(1)
+	for _, rule := range f.rules {
+		v, err := strconv.ParseFloat(rule.Param, 64)
+		if err != nil {
+			continue
+		}
+		step := 1.0
+		if f.class == "float" {
+			step = 0.5
+		}
+		switch rule.Name {
+		case "len":
+			lower, upper = math.Max(lower, v), math.Min(upper, v)
+		case "eq":
+			if f.class == "collection" {
+				lower, upper = math.Max(lower, v), math.Min(upper, v)
+			}
+		case "min", "gte":
+			lower = math.Max(lower, v)
+		case "gt":
+			lower = math.Max(lower, v+step)
+		case "max", "lte":
+			upper = math.Min(upper, v)
+		case "lt":
+			upper = math.Min(upper, v-step)
+		}
+	}
+	return lower, upper
+}
+
+// param 字段规则的参数
+func (f *staticField) param(name string) (string, bool) {
+	for _, rule := range f.rules {
+		if rule.Name == name {
+			return rule.Param, true
+		}
+	}
+	return "", false
+}
+
+// stringSample 满足长度和格式规则的字符串
+func (f *staticField) stringSample() (string, bool) {
+	lower, upper := f.bounds()
+	if param, ok := f.param("oneof"); ok {
+		return strconv.Quote(strings.Fields(param)[0]), true
+	}
+	if param, ok := f.param("eq"); ok {
+		return strconv.Quote(param), true
+	}
+
+	n := int(math.Max(lower, 1))
+	value := strings.Repeat("a", n)
+	for _, rule := range f.rules {
+		switch rule.Name {
+		case "email", "url":
+			value = staticSamples[rule.Name]
+		case "numeric", "number":
+			value = strings.Repeat("1", n)
+		}
+	}
+
+	length := float64(len(value))
+	if length < lower || length > upper {
+		return "", false
+	}
+	if param, ok := f.param("ne"); ok && param == value {
+		return "", false
+	}
+	return strconv.Quote(value), true
+}
+
+// numberSample 满足大小规则的数值
+func (f *staticField) numberSample() (string, bool) {
+	if param, ok := f.param("oneof"); ok {
+		return strings.Fields(param)[0], true
+	}
+	if param, ok := f.param("eq"); ok {
+		return param, true
+	}
+
+	lower, upper := f.bounds()
+	value := 1.0
+	if !math.IsInf(lower, -1) {
+		value = lower
+	}
+	if param, ok := f.param("ne"); ok {
+		if ne, err := strconv.ParseFloat(param, 64); err == nil && ne == value {
+			value++
+		}
+	}
+	if _, ok := f.param("required"); ok && value == 0 {
+		value++
+	}
+	if value > upper || (f.class == "uint" && value < 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), true
+}
+
+// collectionSample 满足长度规则的切片，map只能生成空map
+func (f *staticField) collectionSample() (string, bool) {
+	lower, upper := f.bounds()
+	n := math.Max(lower, 0)
+	if n > upper {
+		return "", false
+	}
+	if f.typ.Kind == KindMap {
+		if n > 0 {
+			return "", false
+		}
+		return f.typ.String() + "{}", true
+	}
+	return fmt.Sprintf("make(%s, %d)", f.typ, int(n)), true
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// staticCase 一个字段的类型、规则，以及用于对比静态检查和validator的取值
+type staticCase struct {
+	typ    string
+	rule   string
+	static bool     // 是否生成静态检查，false表示回退到validator
+	values []string // F字段的Go表达式
+}
+
+var staticCases = []staticCase{
+	{"string", "required,min=2,max=4", true, []string{`""`, `"a"`, `"ab"`, `"abcd"`, `"abcde"`, `"中文"`, `"中文字符串"`}},
+	{"string", "omitempty,alphanum", true, []string{`""`, `"abc123"`, `"abc-123"`}},
+	{"string", "email", true, []string{`""`, `"user@example.com"`, `"user"`}},
+	{"string", "eq=admin", true, []string{`""`, `"admin"`, `"root"`}},
+	{"string", "ne=root", true, []string{`""`, `"admin"`, `"root"`}},
+	{"string", "oneof=red green", true, []string{`""`, `"red"`, `"blue"`}},
+	{"int", "oneof=-1 5", true, []string{`0`, `-1`, `5`, `1`}},
+	{"uint8", "oneof=1 200", true, []string{`0`, `1`, `200`}},
+	{"int", "oneof=0x1 010", false, []string{`0`, `1`, `8`, `10`}},
+	{"int", "oneof=+1 2", false, []string{`1`, `2`}},
+	{"uint", "oneof=1_0", false, []string{`10`, `1`}},
+	{"*string", "required", true, []string{`nil`, `ptr("")`, `ptr("a")`}},
+	{"*string", "min=3", true, []string{`nil`, `ptr("ab")`, `ptr("abc")`}},
+	{"*string", "omitempty,len=3", true, []string{`nil`, `ptr("")`, `ptr("abc")`, `ptr("abcd")`}},
+	{"int", "omitempty,min=10", true, []string{`0`, `5`, `10`, `-1`}},
+	{"*int", "required", true, []string{`nil`, `ptr(0)`, `ptr(1)`}},
+	{"*int", "omitempty,min=10", true, []string{`nil`, `ptr(0)`, `ptr(10)`}},
+	{"uint8", "gte=1,lte=100", true, []string{`0`, `1`, `100`, `101`}},
+	{"float64", "gt=0.5", true, []string{`0`, `0.5`, `0.6`}},
+	{"bool", "required", true, []string{`false`, `true`}},
+	{"[]string", "required,min=1", true, []string{`nil`, `[]string{}`, `[]string{"a"}`}},
+	{"[]string", "omitempty,max=1", true, []string{`nil`, `[]string{}`, `[]string{"a"}`, `[]string{"a", "b"}`}},
+	{"[]string", "eq=2", true, []string{`nil`, `[]string{"a"}`, `[]string{"a", "b"}`}},
+	{"*[]string", "required", true, []string{`nil`, `ptr([]string(nil))`, `ptr([]string{})`, `ptr([]string{"a"})`}},
+	{"*[]string", "omitempty,max=1", true, []string{`nil`, `ptr([]string(nil))`, `ptr([]string{})`, `ptr([]string{"a", "b"})`}},
+	{"*map[string]int", "required", true, []string{`nil`, `ptr(map[string]int(nil))`, `ptr(map[string]int{})`}},
+	{"map[string]int", "omitempty,len=1", true, []string{`nil`, `map[string]int{}`, `map[string]int{"a": 1}`, `map[string]int{"a": 1, "b": 2}`}},
+	{"[]string", "dive,required", false, []string{`nil`, `[]string{"a"}`, `[]string{""}`}},
+	{"[]string", "required,dive,min=2", false, []string{`nil`, `[]string{"ab"}`, `[]string{"a"}`}},
+}
+
+// TestStaticChecksMatchValidator 生成静态模式的验证代码，对同样的输入分别调用生成的Validate方法和validator，
+// 两者对是否合法的判断必须一致
+func TestStaticChecksMatchValidator(t *testing.T) {
+	skipWithoutGo(t)
+	logOutput = io.Discard
+	defer func() { logOutput = os.Stdout }()
+
+	var structs []ValidateStruct
+	for i, c := range staticCases {
+		fieldType, err := parseTypeExpr(c.typ)
+		if err != nil {
+			t.Fatalf("parse %s: %v", c.typ, err)
+		}
+		structs = append(structs, ValidateStruct{
+			Name:   fmt.Sprintf("T%d", i),
+			Fields: []ValidateField{{Name: "F", Type: fieldType, ValidateRule: c.rule, JsonTag: "f"}},
+		})
+	}
+
+	dir := t.TempDir()
+	g := NewValidateGenerator(&plugin.Plugin{Dir: dir, ApiFilePath: filepath.Join(dir, "test.api")}, &Options{Static: true, Package: "main"})
+	static := g.buildStaticCode(newTypeGraph(structs, nil), structs)
+	for i, c := range staticCases {
+		if _, ok := static.Checks[structs[i].Name]; ok != c.static {
+			t.Errorf("%s `validate:\"%s\"`: static checks generated = %v, want %v", c.typ, c.rule, ok, c.static)
+		}
+	}
+
+	files, err := g.renderFiles(dir, structs, nil, &manifest{}, static)
+	if err != nil {
+		t.Fatalf("render files: %v", err)
+	}
+
+	var types, checks strings.Builder
+	for i, c := range staticCases {
+		fmt.Fprintf(&types, "type T%d struct {\n\tF %s `json:\"f\" validate:\"%s\"`\n}\n\n", i, c.typ, c.rule)
+		for _, value := range c.values {
+			fmt.Fprintf(&checks, "\t{\n\t\tv := &T%d{F: %s}\n\t\tcheck(%q, v.Validate(), validate.Struct(v))\n\t}\n",
+				i, value, fmt.Sprintf("%s `validate:\"%s\"` = %s", c.typ, c.rule, value))
+		}
+	}
+	output, err := runGeneratedCode(t, dir, files, "package main\n\n"+types.String(), `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func main() {
+	failed := false
+	check := func(name string, static, reflect error) {
+		if (static == nil) != (reflect == nil) {
+			fmt.Printf("%s: Validate() = %v, validator = %v\n", name, static, reflect)
+			failed = true
+		}
+	}
+`+checks.String()+`	if failed {
+		os.Exit(1)
+	}
+}
+`)
+	if err != nil || len(output) > 0 {
+		t.Fatalf("static checks disagree with validator: %v\n%s", err, output)
+	}
+}
+
+// skipWithoutGo 需要编译运行生成的代码的测试，在 -short 或没有go命令时跳过
+func skipWithoutGo(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compiles and runs generated code")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+}
+
+// runGeneratedCode 在dir中组成一个main包：生成的文件、类型定义types和入口main，
+// 使用example项目的go.mod锁定validator版本，运行后返回输出
+func runGeneratedCode(t *testing.T, dir string, files []outputFile, types, main string) ([]byte, error) {
+	t.Helper()
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".go") {
+			writeTestFile(t, file.Path, file.Content)
+		}
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := os.ReadFile(filepath.Join("..", "example", name))
+		if err != nil {
+			t.Fatalf("read example %s: %v", name, err)
+		}
+		writeTestFile(t, filepath.Join(dir, name), string(content))
+	}
+	writeTestFile(t, filepath.Join(dir, "types.go"), types)
+	writeTestFile(t, filepath.Join(dir, "main.go"), main)
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	return cmd.CombinedOutput()
+}
+
+// writeTestFile 写入测试用的文件
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/zeromicro/go-zero/tools/goctl/util/pathx"
+)
+
+// templateCategory 模板在goctl模板目录中的分类，
+// 自定义模板位于 ~/.goctl/<goctl版本>/validate/ 或 --home 指定的目录
+const templateCategory = "validate"
+
+const (
+	validateTemplateFile         = "validate.tpl"
+	translatorTemplateFile       = "translator.tpl"
+	customTranslatorTemplateFile = "translator_custom.tpl"
+	benchTemplateFile            = "validate_bench.tpl"
+	customValidateTemplateFile   = "validate_custom.tpl"
+)
+
+var (
+	//go:embed tpl/validate.tpl
+	validateTemplate string
+	//go:embed tpl/translator.tpl
+	translatorTemplate string
+	//go:embed tpl/translator_custom.tpl
+	customTranslatorTemplate string
+	//go:embed tpl/validate_bench.tpl
+	benchTemplate string
+	//go:embed tpl/validate_custom.tpl
+	customValidateTemplate string
+)
+
+// templates 内置模板
+var templates = map[string]string{
+	validateTemplateFile:         validateTemplate,
+	translatorTemplateFile:       translatorTemplate,
+	customTranslatorTemplateFile: customTranslatorTemplate,
+	benchTemplateFile:            benchTemplate,
+	customValidateTemplateFile:   customValidateTemplate,
+}
+
+// TemplateData 所有模板共用的数据
+type TemplateData struct {
+	Package            string              // 生成代码的包名
+	EnableTranslator   bool                // 是否生成了翻译器
+	Structs            []ValidateStruct    // 需要生成Validate方法的结构体
+	StructHooks        []string            // 手写了ValidateStruct方法、需要注册结构体级别验证的类型
+	CustomFile         string              // 自定义翻译文件名，例如 translator_custom.go
+	CustomValidateFile string              // 自定义规则文件名，例如 validate_custom.go
+	Receiver           string              // 方法接收者名称，默认为 r
+	Method             string              // 验证方法名，默认为 Validate
+	CtxMethod          string              // 带context的验证方法名，默认为 ValidateCtx
+	PartialMethod      string              // 只验证指定字段的方法名，默认为 ValidatePartial
+	ExceptMethod       string              // 排除指定字段的验证方法名，默认为 ValidateExcept
+	ValidatorVar       string              // 共享validator实例的变量名，默认为 validate
+	TranslatorVar      string              // 翻译器实例的变量名，默认为 translator
+	Static             bool                // 是否为静态模式
+	Checks             map[string][]string // 静态模式下结构体的检查条件，条件为true时交给validator生成错误，没有条件的结构体使用validator
+	Samples            map[string]string   // 静态模式下基准测试使用的合法示例值
+	Imports            []string            // validate.go 额外导入的包
+	Regexps            []StaticRegexp      // validate.go 中预编译的正则表达式
+}
+
+// templateFuncs 模板中可用的函数
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"lowerFirst": lowerFirst,
+	"join":       strings.Join,
+	"quote":      strconv.Quote,
+}
+
+// lowerFirst 首字母小写，例如 UserLoginReq 返回 userLoginReq
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// renderTemplate 渲染模板，模板目录中存在同名的自定义模板时优先使用
+func renderTemplate(name string, data TemplateData) (string, error) {
+	text, err := pathx.LoadTemplate(templateCategory, name, templates[name])
+	if err != nil {
+		return "", fmt.Errorf("failed to load template %s: %v", name, err)
+	}
+
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %v", name, err)
+	}
+	return buf.String(), nil
+}
+
+// TemplateDir 返回自定义模板所在的目录
+func TemplateDir() (string, error) {
+	return pathx.GetTemplateDir(templateCategory)
+}
+
+// InitTemplates 将内置模板写入模板目录，已经存在的模板不会被覆盖
+func InitTemplates() error {
+	return pathx.InitTemplates(templateCategory, templates)
+}
+
+// CleanTemplates 删除模板目录中的所有模板
+func CleanTemplates() error {
+	return pathx.Clean(templateCategory)
+}
+
+// RevertTemplate 将指定的模板恢复为内置模板
+func RevertTemplate(name string) error {
+	content, ok := templates[name]
+	if !ok {
+		return fmt.Errorf("%s: no such template", name)
+	}
+	return pathx.CreateTemplate(templateCategory, name, content)
+}
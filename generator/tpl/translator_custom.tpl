@@ -0,0 +1,28 @@
+package {{.Package}}
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// 重写默认的自定义翻译注册函数
+func init() {
+	getCustomTranslationRegister = func() func(*validator.Validate, ut.Translator) {
+		return registerCustomTranslationsImpl
+	}
+}
+
+// registerCustomTranslationsImpl 注册自定义翻译规则的实现
+// 在这里添加您的自定义验证规则翻译
+// 此文件不会被 goctl-validate 重新生成覆盖
+func registerCustomTranslationsImpl(validate *validator.Validate, translator ut.Translator) {
+	// 示例：注册自定义验证规则翻译
+	// validate.RegisterTranslation("custom_rule", translator, func(ut ut.Translator) error {
+	//     return ut.Add("custom_rule", "{0}不符合自定义规则", true)
+	// }, func(ut ut.Translator, fe validator.FieldError) string {
+	//     t, _ := ut.T("custom_rule", fe.Field())
+	//     return t
+	// })
+
+	// 您可以在这里添加更多自定义翻译规则...
+}
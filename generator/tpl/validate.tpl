@@ -0,0 +1,164 @@
+package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/go-playground/validator/v10"
+)
+
+// 共享的validator实例，创建时调用 {{.CustomValidateFile}} 中的registerCustomValidations注册自定义规则
+{{- if .StructHooks}}，
+// 并注册手写的ValidateStruct方法，其中通过sl.ReportError报告的错误与字段错误一样可以被翻译
+{{- end}}
+var {{.ValidatorVar}} = func() *validator.Validate {
+	v := validator.New()
+	registerCustomValidations(v)
+{{- range .StructHooks}}
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		s := sl.Current().Interface().({{.}})
+		s.ValidateStruct(sl)
+	}, {{.}}{})
+{{- end}}
+	return v
+}()
+{{- if .Regexps}}
+
+// 静态检查使用的正则表达式，匹配成功时validator一定验证通过
+var (
+{{- range .Regexps}}
+	{{.Name}} = regexp.MustCompile({{quote .Pattern}})
+{{- end}}
+)
+{{- end}}
+{{range .Structs}}
+// {{$.Method}} 验证{{.Name}}结构体
+func ({{$.Receiver}} *{{.Name}}) {{$.Method}}() error {
+	return {{$.Receiver}}.{{$.CtxMethod}}(context.Background())
+}
+
+// {{$.CtxMethod}} 使用ctx验证{{.Name}}结构体，通过RegisterValidationCtx注册的自定义规则可以获取到ctx
+func ({{$.Receiver}} *{{.Name}}) {{$.CtxMethod}}(ctx context.Context) error {
+{{- with index $.Checks .Name}}
+	// 静态检查全部通过时不使用反射，否则交给validator生成与原来相同的错误
+	if {{$.Receiver}} == nil {
+		return {{$.ValidatorVar}}.StructCtx(ctx, {{$.Receiver}})
+	}
+{{- range .}}
+	if {{.}} {
+		return {{$.ValidatorVar}}.StructCtx(ctx, {{$.Receiver}})
+	}
+{{- end}}
+	return nil
+{{- else}}
+	return {{$.ValidatorVar}}.StructCtx(ctx, {{$.Receiver}})
+{{- end}}
+}
+
+// {{$.PartialMethod}} 只验证{{.Name}}的指定字段，字段使用Go字段名，嵌套字段形如 Address.City
+func ({{$.Receiver}} *{{.Name}}) {{$.PartialMethod}}(fields ...string) error {
+	return {{$.ValidatorVar}}.StructPartial({{$.Receiver}}, fields...)
+}
+
+// {{$.ExceptMethod}} 验证{{.Name}}除指定字段以外的所有字段
+func ({{$.Receiver}} *{{.Name}}) {{$.ExceptMethod}}(fields ...string) error {
+	return {{$.ValidatorVar}}.StructExcept({{$.Receiver}}, fields...)
+}
+{{end}}
+// JSONFields 返回JSON请求体中出现的键对应的字段名，用于PATCH等部分更新请求只验证提交的字段：
+//
+//	fields, err := JSONFields(body, req)
+//	if err != nil {
+//		return err
+//	}
+//	return req.{{.PartialMethod}}(fields...)
+//
+// 嵌套对象展开为 Address.City，数组展开为 Items[0].Name，JSON键与json标签按大小写精确匹配，
+// v必须是结构体或结构体指针
+func JSONFields(body []byte, v interface{}) ([]string, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("JSONFields: %T is not a struct or a pointer to struct", v)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	return jsonFields(raw, t, ""), nil
+}
+
+// jsonFields 递归收集结构体类型t中出现在raw里的字段
+func jsonFields(raw map[string]json.RawMessage, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		// 没有json标签的内嵌结构体，其字段在JSON中与外层字段平铺
+		if field.Anonymous && name == "" {
+			fields = append(fields, jsonFields(raw, field.Type, prefix+field.Name+".")...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		fields = append(fields, prefix+field.Name)
+		fields = append(fields, nestedJSONFields(value, field.Type, prefix+field.Name)...)
+	}
+	return fields
+}
+
+// nestedJSONFields 展开嵌套对象和数组中出现的字段
+func nestedJSONFields(value json.RawMessage, t reflect.Type, name string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(value, &raw) != nil {
+			return nil
+		}
+		return jsonFields(raw, t, name+".")
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			return nil
+		}
+		var fields []string
+		for i, item := range items {
+			index := name + "[" + strconv.Itoa(i) + "]"
+			fields = append(fields, index)
+			fields = append(fields, nestedJSONFields(item, t.Elem(), index)...)
+		}
+		return fields
+	}
+	return nil
+}
\ No newline at end of file
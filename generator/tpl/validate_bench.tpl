@@ -0,0 +1,25 @@
+package {{.Package}}
+
+import "testing"
+{{range .Structs}}{{$name := .Name}}{{with index $.Samples $name}}
+// Benchmark{{$name}}{{$.Method}} 对比静态检查与validator验证{{$name}}的性能
+func Benchmark{{$name}}{{$.Method}}(b *testing.B) {
+	sample := {{.}}
+	if err := sample.{{$.Method}}(); err != nil {
+		b.Skipf("sample value is not valid: %v", err)
+	}
+
+	b.Run("static", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = sample.{{$.Method}}()
+		}
+	})
+	b.Run("validator", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = {{$.ValidatorVar}}.Struct(sample)
+		}
+	})
+}
+{{end}}{{end}}
@@ -0,0 +1,17 @@
+package {{.Package}}
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// registerCustomValidations 注册自定义验证规则，在创建共享的validator实例时调用
+// 在这里注册API文件validate标签中使用的自定义规则，生成时会检查标签中的规则是否已经注册
+// 此文件不会被 goctl-validate 重新生成覆盖
+func registerCustomValidations(validate *validator.Validate) {
+	// 示例：注册自定义验证规则，规则名需要使用字符串字面量
+	// validate.RegisterValidation("mobile", func(fl validator.FieldLevel) bool {
+	//     return mobilePattern.MatchString(fl.Field().String())
+	// })
+
+	// 您可以在这里添加更多自定义验证规则...
+}
@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// TypeKind 字段类型的种类
+type TypeKind int
+
+const (
+	KindBasic     TypeKind = iota // 内置类型，如 string、int64、bool
+	KindNamed                     // 命名类型，如 OrderItem、time.Time
+	KindPointer                   // 指针，如 *OrderItem
+	KindSlice                     // 切片，如 []int
+	KindArray                     // 数组，如 [3]int
+	KindMap                       // map，如 map[string]int
+	KindInterface                 // interface{} 或 any
+	KindStruct                    // 内联匿名结构体
+)
+
+// FieldType 字段的类型表达式
+type FieldType struct {
+	Kind TypeKind   `json:"kind"`
+	Name string     `json:"name,omitempty"` // 内置类型和命名类型的名称，命名类型可以带包名
+	Len  string     `json:"len,omitempty"`  // 数组长度
+	Key  *FieldType `json:"key,omitempty"`  // map的key类型
+	Elem *FieldType `json:"elem,omitempty"` // 指针、切片、数组和map的元素类型
+}
+
+// basicTypes Go内置类型
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true, "error": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// String 返回类型的Go表达式
+func (t *FieldType) String() string {
+	if t == nil {
+		return ""
+	}
+
+	switch t.Kind {
+	case KindPointer:
+		return "*" + t.Elem.String()
+	case KindSlice:
+		return "[]" + t.Elem.String()
+	case KindArray:
+		return "[" + t.Len + "]" + t.Elem.String()
+	case KindMap:
+		return "map[" + t.Key.String() + "]" + t.Elem.String()
+	case KindInterface:
+		return "interface{}"
+	case KindStruct:
+		return "struct"
+	default:
+		return t.Name
+	}
+}
+
+// Deref 去掉所有指针后的类型
+func (t *FieldType) Deref() *FieldType {
+	for t != nil && t.Kind == KindPointer {
+		t = t.Elem
+	}
+	return t
+}
+
+// IsCollection 是否为可以dive的类型（切片、数组和map）
+func (t *FieldType) IsCollection() bool {
+	return t != nil && (t.Kind == KindSlice || t.Kind == KindArray || t.Kind == KindMap)
+}
+
+// parseTypeExpr 解析字段类型表达式，支持指针、多级切片、数组、map、带包名的类型和interface{}
+func parseTypeExpr(expr string) (*FieldType, error) {
+	p := &typeExprParser{src: expr}
+	t, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %v", expr, err)
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("invalid type %q: unexpected %q", expr, p.src[p.pos:])
+	}
+	return t, nil
+}
+
+// typeExprParser 类型表达式的递归下降解析器
+type typeExprParser struct {
+	src string
+	pos int
+}
+
+func (p *typeExprParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// consume 跳过空白后尝试匹配指定的字符串
+func (p *typeExprParser) consume(s string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// ident 读取标识符，支持 pkg.Type 形式
+func (p *typeExprParser) ident() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := rune(p.src[p.pos])
+		if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *typeExprParser) parse() (*FieldType, error) {
+	switch {
+	case p.consume("*"):
+		elem, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		return &FieldType{Kind: KindPointer, Elem: elem}, nil
+	case p.consume("["):
+		length := p.ident()
+		if !p.consume("]") {
+			return nil, fmt.Errorf("missing ']'")
+		}
+		elem, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		if length == "" {
+			return &FieldType{Kind: KindSlice, Elem: elem}, nil
+		}
+		return &FieldType{Kind: KindArray, Len: length, Elem: elem}, nil
+	}
+
+	name := p.ident()
+	switch name {
+	case "":
+		return nil, fmt.Errorf("missing type name")
+	case "map":
+		if !p.consume("[") {
+			return nil, fmt.Errorf("missing '[' after map")
+		}
+		key, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, fmt.Errorf("missing ']' after map key")
+		}
+		elem, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		return &FieldType{Kind: KindMap, Key: key, Elem: elem}, nil
+	case "interface":
+		if !p.consume("{") || !p.consume("}") {
+			return nil, fmt.Errorf("only empty interface{} is supported")
+		}
+		return &FieldType{Kind: KindInterface}, nil
+	case "any":
+		return &FieldType{Kind: KindInterface}, nil
+	case "struct":
+		return &FieldType{Kind: KindStruct}, nil
+	}
+
+	if basicTypes[name] {
+		return &FieldType{Kind: KindBasic, Name: name}, nil
+	}
+	return &FieldType{Kind: KindNamed, Name: name}, nil
+}
+
+// convertSpecType 将ApiSpec的类型转换为FieldType
+func convertSpecType(t spec.Type) (*FieldType, error) {
+	switch v := t.(type) {
+	case spec.PrimitiveType:
+		return parseTypeExpr(v.RawName)
+	case spec.DefineStruct:
+		return &FieldType{Kind: KindNamed, Name: v.RawName}, nil
+	case spec.NestedStruct:
+		return &FieldType{Kind: KindStruct}, nil
+	case spec.InterfaceType:
+		return &FieldType{Kind: KindInterface}, nil
+	case spec.PointerType:
+		elem, err := convertSpecType(v.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &FieldType{Kind: KindPointer, Elem: elem}, nil
+	case spec.ArrayType:
+		elem, err := convertSpecType(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &FieldType{Kind: KindSlice, Elem: elem}, nil
+	case spec.MapType:
+		key, err := parseTypeExpr(v.Key)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := convertSpecType(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &FieldType{Kind: KindMap, Key: key, Elem: elem}, nil
+	default:
+		return parseTypeExpr(t.Name())
+	}
+}
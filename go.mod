@@ -2,7 +2,10 @@ module goctl-validate
 
 go 1.24
 
-require github.com/zeromicro/go-zero/tools/goctl v1.8.4
+require (
+	github.com/zeromicro/go-zero/tools/goctl v1.8.4
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/fatih/structtag v1.2.0 // indirect
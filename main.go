@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 
 	"goctl-validate/generator"
@@ -11,19 +12,33 @@ import (
 	"github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
-const Version = "v2.0.0"
-
-var (
-	version    = flag.Bool("version", false, "show version and exit")
-	help       = flag.Bool("help", false, "show help and exit")
-	translator = flag.Bool("translator", false, "generate translator for validation messages")
-)
-
 func main() {
-	flag.Parse()
+	// 独立使用的子命令，不需要goctl通过标准输入传入插件上下文
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "gen":
+			runGen(os.Args[2:])
+			return
+		case "config":
+			runConfig(os.Args[2:])
+			return
+		case "template":
+			runTemplate(os.Args[2:])
+			return
+		case "clean":
+			runClean(os.Args[2:])
+			return
+		}
+	}
+
+	fs := flag.NewFlagSet("goctl-validate", flag.ExitOnError)
+	version := fs.Bool("version", false, "show version and exit")
+	help := fs.Bool("help", false, "show help and exit")
+	bindOptionFlags(fs)
+	fs.Parse(os.Args[1:])
 
 	if *version {
-		fmt.Printf("goctl-validate %s %s/%s\n", Version, runtime.GOOS, runtime.GOARCH)
+		fmt.Printf("goctl-validate %s %s/%s\n", generator.Version, runtime.GOOS, runtime.GOARCH)
 		return
 	}
 
@@ -39,23 +54,31 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 检查环境变量
-	enableTranslator := *translator
-	if os.Getenv("GOCTL_VALIDATE_TRANSLATOR") == "true" {
-		enableTranslator = true
+	generate(fs, p)
+}
+
+// generate 合并配置后运行生成器，失败时以非零状态退出
+func generate(fs *flag.FlagSet, p *plugin.Plugin) {
+	cfg, err := loadConfig(fs, filepath.Dir(p.ApiFilePath))
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
 	}
 
-	// 使用简化的生成器
-	gen := generator.NewValidateGenerator(p, &generator.Options{
-		EnableTranslator: enableTranslator,
-	})
+	if err := registerTemplateHome(cfg.string("home"), cfg.string("remote"), cfg.string("branch")); err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
 
+	gen := generator.NewValidateGenerator(p, cfg.options())
 	if err := gen.Generate(); err != nil {
 		fmt.Printf("goctl-validate: %s\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("goctl-validate: validation code generated successfully")
+	if !cfg.bool("check") && !cfg.bool("stdout") {
+		fmt.Println("goctl-validate: validation code generated successfully")
+	}
 }
 
 func showHelp() {
@@ -63,11 +86,26 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  goctl api plugin -plugin goctl-validate -api example.api -dir .")
+	fmt.Println("  goctl-validate gen -api example.api -dir . [-style gozero] [options]")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  -version      show version and exit")
-	fmt.Println("  -help         show help and exit")
-	fmt.Println("  -translator   generate translator for validation messages (default: false)")
+	fmt.Println("  -version         show version and exit")
+	fmt.Println("  -help            show help and exit")
+	for _, spec := range optionSpecs {
+		fmt.Printf("  -%-15s %s\n", spec.Name, spec.Usage)
+	}
+	fmt.Println()
+	fmt.Println("Configuration:")
+	fmt.Printf("  Every option can also be set in %s (searched upward from the api file)\n", configFileName)
+	fmt.Println("  or via environment variables: " + envList())
+	fmt.Println("  Precedence: flag > environment variable > config file > default")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  gen           run the generator without goctl, e.g. from go generate or a Makefile")
+	fmt.Println("                -api and -dir (default: .) replace goctl's plugin input, -style sets the file naming style")
+	fmt.Println("  config print  show the effective value of every option and where it came from")
+	fmt.Println("  clean         remove the generated files listed in the manifest: clean [-dir .] [-output dir] [-style gozero]")
+	fmt.Println("  template      manage code templates: template init|clean|revert -name validate.tpl [-home dir] [-remote repo]")
 	fmt.Println()
 	fmt.Println("Features:")
 	fmt.Println("  - Generates Validate() methods for request structures")
@@ -78,7 +116,7 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("How it works:")
 	fmt.Println("  1. Parses API file for structures with validate tags")
-	fmt.Println("  2. Generates validate.go in internal/types directory")
+	fmt.Println("  2. Generates validate.go in the output directory (internal/types by default)")
 	fmt.Println("  3. Creates Validate() method for each structure")
 	fmt.Println("  4. Uses shared 'var validate = validator.New()' instance")
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"goctl-validate/generator"
+
+	"github.com/zeromicro/go-zero/tools/goctl/util"
+	"github.com/zeromicro/go-zero/tools/goctl/util/pathx"
+)
+
+// registerTemplateHome 按照goctl的方式确定模板目录：
+// 指定remote时克隆远程仓库作为模板目录，否则使用home，都没有指定时使用 ~/.goctl
+func registerTemplateHome(home, remote, branch string) error {
+	if remote != "" {
+		repo, err := util.CloneIntoGitHome(remote, branch)
+		if err != nil {
+			return fmt.Errorf("failed to clone template repo %s: %v", remote, err)
+		}
+		home = repo
+	}
+
+	if home != "" {
+		pathx.RegisterGoctlHome(home)
+	}
+	return nil
+}
+
+// runTemplate template子命令：init写入内置模板，clean删除所有模板，revert恢复单个模板。
+// 模板目录与生成时相同，由 -home、-remote、-branch、环境变量和当前目录向上查找到的配置文件决定
+func runTemplate(args []string) {
+	if len(args) == 0 {
+		templateUsage()
+	}
+
+	fs := flag.NewFlagSet("goctl-validate template "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "the template file to revert, e.g. validate.tpl")
+	bindOptionFlags(fs)
+	fs.Parse(args[1:])
+
+	cfg, err := loadConfig(fs, ".")
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+	if err := registerTemplateHome(cfg.string("home"), cfg.string("remote"), cfg.string("branch")); err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+
+	dir, err := generator.TemplateDir()
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "init":
+		err = generator.InitTemplates()
+		if err == nil {
+			fmt.Printf("goctl-validate: templates are generated in %s, edit on demand\n", dir)
+		}
+	case "clean":
+		err = generator.CleanTemplates()
+		if err == nil {
+			fmt.Printf("goctl-validate: templates are cleaned in %s\n", dir)
+		}
+	case "revert":
+		if *name == "" {
+			fmt.Println("goctl-validate: missing -name")
+			os.Exit(2)
+		}
+		err = generator.RevertTemplate(*name)
+		if err == nil {
+			fmt.Printf("goctl-validate: %s is reverted in %s\n", *name, dir)
+		}
+	default:
+		templateUsage()
+	}
+
+	if err != nil {
+		fmt.Printf("goctl-validate: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func templateUsage() {
+	fmt.Println("Usage: goctl-validate template init|clean|revert [-home dir] [-remote repo] [-branch branch] [-name validate.tpl]")
+	os.Exit(2)
+}
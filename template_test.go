@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunTemplateUsesConfig template子命令与生成时一样从配置文件和环境变量读取模板目录
+func TestRunTemplateUsesConfig(t *testing.T) {
+	for _, spec := range optionSpecs {
+		t.Setenv(spec.Env, "")
+	}
+
+	project := t.TempDir()
+	fileHome := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, configFileName), []byte("home: "+fileHome+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	workDir := filepath.Join(project, "api")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workDir)
+
+	envHome := t.TempDir()
+	flagHome := t.TempDir()
+	steps := []struct {
+		env  string
+		args []string
+		home string
+	}{
+		{args: []string{"init"}, home: fileHome},
+		{env: envHome, args: []string{"init"}, home: envHome},
+		{env: envHome, args: []string{"init", "-home", flagHome}, home: flagHome},
+	}
+
+	for _, step := range steps {
+		t.Setenv("GOCTL_VALIDATE_HOME", step.env)
+		runTemplate(step.args)
+		if _, err := os.Stat(filepath.Join(step.home, "validate", "validate.tpl")); err != nil {
+			t.Errorf("template %v with GOCTL_VALIDATE_HOME=%q: templates not written to %s: %v", step.args, step.env, step.home, err)
+		}
+	}
+}